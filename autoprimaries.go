@@ -0,0 +1,64 @@
+package powerdns
+
+import (
+	"context"
+	"net/http"
+	"path"
+)
+
+// AutoprimariesService handles communication with the autoprimaries related methods of the Client API
+type AutoprimariesService service
+
+// Autoprimary structure with JSON API metadata.
+// A secondary server provisions zones automatically if a NOTIFY is received from IP and NameServer is listed in the NS RRset of the zone.
+type Autoprimary struct {
+	IP         *string `json:"ip,omitempty"`
+	NameServer *string `json:"nameserver,omitempty"`
+	Account    *string `json:"account,omitempty"`
+}
+
+// List retrieves a list of Autoprimaries
+func (a *AutoprimariesService) List(ctx context.Context) ([]Autoprimary, error) {
+	ctx = withOperation(ctx, Operation{Service: "Autoprimaries", Method: "List"})
+
+	req, err := a.client.newRequest(ctx, http.MethodGet, path.Join("servers", a.client.VHost, "autoprimaries"), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	autoprimaries := make([]Autoprimary, 0)
+	_, err = a.client.do(req, &autoprimaries)
+	return autoprimaries, err
+}
+
+// Add registers a new Autoprimary, whose provisioned zones belong to account
+func (a *AutoprimariesService) Add(ctx context.Context, ip, nameServer, account string) error {
+	ctx = withOperation(ctx, Operation{Service: "Autoprimaries", Method: "Add"})
+
+	autoprimary := Autoprimary{
+		IP:         &ip,
+		NameServer: &nameServer,
+		Account:    &account,
+	}
+
+	req, err := a.client.newRequest(ctx, http.MethodPost, path.Join("servers", a.client.VHost, "autoprimaries"), nil, autoprimary)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.client.do(req, nil)
+	return err
+}
+
+// Delete removes a given Autoprimary
+func (a *AutoprimariesService) Delete(ctx context.Context, ip, nameServer string) error {
+	ctx = withOperation(ctx, Operation{Service: "Autoprimaries", Method: "Delete"})
+
+	req, err := a.client.newRequest(ctx, http.MethodDelete, path.Join("servers", a.client.VHost, "autoprimaries", ip, nameServer), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.client.do(req, nil)
+	return err
+}
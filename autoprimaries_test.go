@@ -0,0 +1,141 @@
+package powerdns
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func registerAutoprimariesMockResponder(autoprimaries *[]Autoprimary) {
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/autoprimaries",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, autoprimaries)
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodPost, generateTestAPIVHostURL()+"/autoprimaries",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			var autoprimary Autoprimary
+			if json.NewDecoder(req.Body).Decode(&autoprimary) != nil || autoprimary.IP == nil || autoprimary.NameServer == nil {
+				return httpmock.NewBytesResponse(http.StatusBadRequest, []byte{}), nil
+			}
+			*autoprimaries = append(*autoprimaries, autoprimary)
+			return httpmock.NewBytesResponse(http.StatusCreated, []byte{}), nil
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodDelete, `=~^`+generateTestAPIVHostURL()+`/autoprimaries/([^/]+)/([^/]+)\z`,
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			i := findAutoprimary(*autoprimaries, httpmock.MustGetSubmatch(req, 1), httpmock.MustGetSubmatch(req, 2))
+			if i < 0 {
+				return httpmock.NewBytesResponse(http.StatusNotFound, []byte{}), nil
+			}
+			*autoprimaries = slices.Delete(*autoprimaries, i, i+1)
+			return httpmock.NewBytesResponse(http.StatusNoContent, []byte{}), nil
+		},
+	)
+}
+
+func findAutoprimary(autoprimaries []Autoprimary, ip, nameServer string) int {
+	return slices.IndexFunc(autoprimaries, func(autoprimary Autoprimary) bool {
+		return *autoprimary.IP == ip && *autoprimary.NameServer == nameServer
+	})
+}
+
+func TestListAutoprimaries(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerAutoprimariesMockResponder(&[]Autoprimary{})
+
+	p := initialisePowerDNSTestClient()
+	if err := p.Autoprimaries.Add(context.Background(), "192.0.2.1", "ns1.example.com", ""); err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() { _ = p.Autoprimaries.Delete(context.Background(), "192.0.2.1", "ns1.example.com") }()
+
+	autoprimaries, err := p.Autoprimaries.List(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+	}
+	if findAutoprimary(autoprimaries, "192.0.2.1", "ns1.example.com") < 0 {
+		t.Error("Received invalid list of autoprimaries")
+	}
+}
+
+func TestListAutoprimariesError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if _, err := p.Autoprimaries.List(context.Background()); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestAddAutoprimary(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerAutoprimariesMockResponder(&[]Autoprimary{})
+
+	p := initialisePowerDNSTestClient()
+	if err := p.Autoprimaries.Add(context.Background(), "2001:db8::1", "ns1.example.com", "example"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() { _ = p.Autoprimaries.Delete(context.Background(), "2001:db8::1", "ns1.example.com") }()
+
+	autoprimaries, err := p.Autoprimaries.List(context.Background())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if i := findAutoprimary(autoprimaries, "2001:db8::1", "ns1.example.com"); i < 0 || *autoprimaries[i].Account != "example" {
+		t.Errorf("unexpected autoprimaries %+v", autoprimaries)
+	}
+}
+
+func TestAddAutoprimaryError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if err := p.Autoprimaries.Add(context.Background(), "2001:db8::1", "ns1.example.com", ""); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestDeleteAutoprimary(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerAutoprimariesMockResponder(&[]Autoprimary{})
+
+	p := initialisePowerDNSTestClient()
+	if err := p.Autoprimaries.Add(context.Background(), "2001:db8::2", "ns1.example.com", ""); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err := p.Autoprimaries.Delete(context.Background(), "2001:db8::2", "ns1.example.com"); err != nil {
+		t.Errorf("%s", err)
+	}
+
+	autoprimaries, err := p.Autoprimaries.List(context.Background())
+	if err != nil || findAutoprimary(autoprimaries, "2001:db8::2", "ns1.example.com") >= 0 {
+		t.Errorf("unexpected autoprimaries %+v: %v", autoprimaries, err)
+	}
+}
+
+func TestDeleteAutoprimaryError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if err := p.Autoprimaries.Delete(context.Background(), "2001:db8::1", "ns1.example.com"); err == nil {
+		t.Error("error is nil")
+	}
+}
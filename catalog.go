@@ -0,0 +1,131 @@
+package powerdns
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CatalogService handles catalog zones according to RFC 9432, which provision their member zones on secondary servers.
+// PowerDNS generates the member records of a producer zone from the catalog attribute of its members when the zone is transferred,
+// so they are neither stored nor returned by the API. Member properties like groups belong to the member zones as well:
+// PowerDNS keeps them as producer options, which can only be set by "pdnsutil set-option <zone> producer group <group>",
+// so the groups managed by SetGroups are stored as MetadataCatalogGroup of the member zone, from which these options can be provisioned.
+type CatalogService service
+
+// MetadataCatalogGroup is the custom metadata kind of a member zone, which holds its catalog groups
+const MetadataCatalogGroup MetadataKind = "X-CATALOG-GROUP"
+
+// Create adds a new producer zone, which serves as catalog.
+// Its NS RRset consists of the invalid name, since a catalog zone is not meant to be queried.
+func (c *CatalogService) Create(ctx context.Context, catalog string) (*Zone, error) {
+	ctx = withOperation(ctx, Operation{Service: "Catalog", Method: "Create", Zone: makeDomainCanonical(catalog)})
+
+	zone := &Zone{
+		Name:        String(catalog),
+		Kind:        ZoneKindPtr(ProducerZoneKind),
+		Nameservers: []string{"invalid."},
+	}
+	return c.client.Zones.Add(ctx, zone)
+}
+
+// AddMember adds the zone member to catalog by setting its catalog attribute.
+// It returns ErrNotProducerZone if catalog is not a producer zone.
+func (c *CatalogService) AddMember(ctx context.Context, catalog, member string) error {
+	ctx = withOperation(ctx, Operation{Service: "Catalog", Method: "AddMember", Zone: makeDomainCanonical(catalog)})
+
+	zone, err := c.client.Zones.Get(ctx, catalog, WithoutRRsets())
+	if err != nil {
+		return err
+	}
+	if zone.Kind == nil || *zone.Kind != ProducerZoneKind {
+		return fmt.Errorf("%w: %s", ErrNotProducerZone, makeDomainCanonical(catalog))
+	}
+
+	return c.client.Zones.Change(ctx, member, &Zone{Catalog: String(makeDomainCanonical(catalog))})
+}
+
+// RemoveMember removes the zone member from catalog by clearing its catalog attribute and its groups.
+// It returns ErrNotCatalogMember if the zone is not a member of catalog.
+func (c *CatalogService) RemoveMember(ctx context.Context, catalog, member string) error {
+	ctx = withOperation(ctx, Operation{Service: "Catalog", Method: "RemoveMember", Zone: makeDomainCanonical(catalog)})
+
+	if err := c.checkMember(ctx, catalog, member); err != nil {
+		return err
+	}
+	if err := c.client.Zones.Change(ctx, member, &Zone{Catalog: String("")}); err != nil {
+		return err
+	}
+	return c.client.Metadata.Delete(ctx, member, MetadataCatalogGroup)
+}
+
+// SetGroups replaces the groups of the zone member in catalog. No groups remove them.
+// It returns ErrNotCatalogMember if the zone is not a member of catalog.
+func (c *CatalogService) SetGroups(ctx context.Context, catalog, member string, groups ...string) error {
+	ctx = withOperation(ctx, Operation{Service: "Catalog", Method: "SetGroups", Zone: makeDomainCanonical(catalog)})
+
+	if err := c.checkMember(ctx, catalog, member); err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return c.client.Metadata.Delete(ctx, member, MetadataCatalogGroup)
+	}
+	_, err := c.client.Metadata.Set(ctx, member, MetadataCatalogGroup, groups)
+	return err
+}
+
+// Groups retrieves the groups of the zone member in catalog.
+// It returns ErrNotCatalogMember if the zone is not a member of catalog.
+func (c *CatalogService) Groups(ctx context.Context, catalog, member string) ([]string, error) {
+	ctx = withOperation(ctx, Operation{Service: "Catalog", Method: "Groups", Zone: makeDomainCanonical(catalog)})
+
+	if err := c.checkMember(ctx, catalog, member); err != nil {
+		return nil, err
+	}
+	metadata, err := c.client.Metadata.Get(ctx, member, MetadataCatalogGroup)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.Metadata, nil
+}
+
+// Members retrieves the member zones of catalog, i.e. the zones whose catalog attribute refers to it, ordered by name.
+// The RRsets of the member zones are not retrieved.
+func (c *CatalogService) Members(ctx context.Context, catalog string) ([]Zone, error) {
+	ctx = withOperation(ctx, Operation{Service: "Catalog", Method: "Members", Zone: makeDomainCanonical(catalog)})
+
+	if _, err := c.client.Zones.Get(ctx, catalog, WithoutRRsets()); err != nil {
+		return nil, err
+	}
+
+	zones, err := c.client.Zones.List(ctx, WithoutDNSSEC())
+	if err != nil {
+		return nil, err
+	}
+
+	members := slices.DeleteFunc(zones, func(zone Zone) bool {
+		return !isCatalogMember(&zone, catalog)
+	})
+	slices.SortFunc(members, func(a, b Zone) int {
+		return cmp.Compare(StringValue(a.Name), StringValue(b.Name))
+	})
+	return members, nil
+}
+
+// checkMember returns ErrNotCatalogMember if the zone member is not a member of catalog
+func (c *CatalogService) checkMember(ctx context.Context, catalog, member string) error {
+	zone, err := c.client.Zones.Get(ctx, member, WithoutRRsets())
+	if err != nil {
+		return err
+	}
+	if !isCatalogMember(zone, catalog) {
+		return fmt.Errorf("%w: %s is not a member of %s", ErrNotCatalogMember, makeDomainCanonical(member), makeDomainCanonical(catalog))
+	}
+	return nil
+}
+
+func isCatalogMember(zone *Zone, catalog string) bool {
+	return StringValue(zone.Catalog) != "" && strings.EqualFold(makeDomainCanonical(StringValue(zone.Catalog)), makeDomainCanonical(catalog))
+}
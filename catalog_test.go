@@ -0,0 +1,255 @@
+package powerdns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// registerCatalogMockResponder serves the given zones and stores zones which are created or changed, as well as their catalog groups
+func registerCatalogMockResponder(zoneNames ...string) {
+	zones := make(map[string]Zone)
+	groups := make(map[string][]string)
+	for _, name := range zoneNames {
+		zones[makeDomainCanonical(name)] = Zone{ID: String(makeDomainCanonical(name)), Name: String(makeDomainCanonical(name)), Kind: ZoneKindPtr(NativeZoneKind), Catalog: String("")}
+	}
+
+	zoneResponder := func(req *http.Request) (*http.Response, error) {
+		if res := verifyAPIKey(req); res != nil {
+			return res, nil
+		}
+
+		name := strings.ToLower(httpmock.MustGetSubmatch(req, 1))
+		zone, ok := zones[name]
+		if !ok {
+			return httpmock.NewJsonResponse(http.StatusNotFound, Error{Message: fmt.Sprintf("Could not find domain '%s'", name)})
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+			if req.URL.Query().Get("rrsets") != "false" {
+				return httpmock.NewStringResponse(http.StatusBadRequest, "RRsets have been requested"), nil
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, zone)
+		default:
+			var change Zone
+			if json.NewDecoder(req.Body).Decode(&change) != nil || change.Catalog == nil {
+				return httpmock.NewBytesResponse(http.StatusBadRequest, []byte{}), nil
+			}
+			zone.Catalog = change.Catalog
+			zones[name] = zone
+			return httpmock.NewBytesResponse(http.StatusNoContent, []byte{}), nil
+		}
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPut} {
+		httpmock.RegisterResponder(method, `=~^`+generateTestAPIVHostURL()+`/zones/([^/?]+)(?:\?|\z)`, zoneResponder)
+	}
+
+	groupsResponder := func(req *http.Request) (*http.Response, error) {
+		if res := verifyAPIKey(req); res != nil {
+			return res, nil
+		}
+
+		name := httpmock.MustGetSubmatch(req, 1)
+		switch req.Method {
+		case http.MethodGet:
+			return httpmock.NewJsonResponse(http.StatusOK, Metadata{Kind: MetadataKindPtr(MetadataCatalogGroup), Metadata: groups[name]})
+		case http.MethodPut:
+			var metadata Metadata
+			if json.NewDecoder(req.Body).Decode(&metadata) != nil || len(metadata.Metadata) == 0 {
+				return httpmock.NewBytesResponse(http.StatusBadRequest, []byte{}), nil
+			}
+			groups[name] = metadata.Metadata
+			return httpmock.NewJsonResponse(http.StatusOK, metadata)
+		default:
+			delete(groups, name)
+			return httpmock.NewBytesResponse(http.StatusNoContent, []byte{}), nil
+		}
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		httpmock.RegisterResponder(method, `=~^`+generateTestAPIVHostURL()+`/zones/([^/]+)/metadata/`+string(MetadataCatalogGroup)+`\z`, groupsResponder)
+	}
+
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/zones",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+			list := make([]Zone, 0, len(zones))
+			for _, zone := range zones {
+				list = append(list, zone)
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, list)
+		},
+	)
+	httpmock.RegisterResponder(http.MethodPost, generateTestAPIVHostURL()+"/zones",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+			var zone Zone
+			if json.NewDecoder(req.Body).Decode(&zone) != nil || !slices.Equal(zone.Nameservers, []string{"invalid."}) {
+				return httpmock.NewBytesResponse(http.StatusBadRequest, []byte{}), nil
+			}
+			name := makeDomainCanonical(*zone.Name)
+			zones[name] = Zone{ID: String(name), Name: String(name), Kind: zone.Kind, Catalog: String("")}
+			return httpmock.NewJsonResponse(http.StatusCreated, zones[name])
+		},
+	)
+}
+
+func TestCatalog(t *testing.T) {
+	catalog := fmt.Sprintf("catalog-%d.com", rand.Int())
+	members := []string{generateNativeZone(true), generateNativeZone(true)}
+	slices.Sort(members)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerCatalogMockResponder(members...)
+
+	p := initialisePowerDNSTestClient()
+	ctx := context.Background()
+	zone, err := p.Catalog.Create(ctx, catalog)
+	if err != nil || *zone.Kind != ProducerZoneKind {
+		t.Fatalf("unexpected catalog %+v: %v", zone, err)
+	}
+
+	for _, member := range members {
+		if err := p.Catalog.AddMember(ctx, strings.ToUpper(catalog), member); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if err := p.Catalog.AddMember(ctx, members[0], members[1]); !errors.Is(err, ErrNotProducerZone) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := p.Catalog.AddMember(ctx, "unknown-"+catalog, members[1]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	zones, err := p.Catalog.Members(ctx, catalog)
+	if err != nil || len(zones) != 2 || *zones[0].Name != makeDomainCanonical(members[0]) || *zones[1].Name != makeDomainCanonical(members[1]) || !strings.EqualFold(*zones[0].Catalog, makeDomainCanonical(catalog)) {
+		t.Errorf("unexpected members %+v: %v", zones, err)
+	}
+
+	if err := p.Catalog.SetGroups(ctx, catalog, members[0], "signed", "internal"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if groups, err := p.Catalog.Groups(ctx, catalog, members[0]); err != nil || !slices.Equal(groups, []string{"signed", "internal"}) {
+		t.Errorf("unexpected groups %v: %v", groups, err)
+	}
+	if err := p.Catalog.SetGroups(ctx, catalog, members[1], "signed"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := p.Catalog.SetGroups(ctx, catalog, members[1]); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if groups, err := p.Catalog.Groups(ctx, catalog, members[1]); err != nil || len(groups) != 0 {
+		t.Errorf("unexpected groups %v: %v", groups, err)
+	}
+
+	if err := p.Catalog.RemoveMember(ctx, catalog, members[0]); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := p.Catalog.RemoveMember(ctx, catalog, members[0]); !errors.Is(err, ErrNotCatalogMember) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := p.Catalog.SetGroups(ctx, catalog, members[0], "signed"); !errors.Is(err, ErrNotCatalogMember) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := p.Catalog.Groups(ctx, catalog, members[0]); !errors.Is(err, ErrNotCatalogMember) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if metadata, err := p.Metadata.Get(ctx, members[0], MetadataCatalogGroup); err != nil || len(metadata.Metadata) != 0 {
+		t.Errorf("groups have not been removed with the member: %+v: %v", metadata, err)
+	}
+	zones, err = p.Catalog.Members(ctx, catalog)
+	if err != nil || len(zones) != 1 || *zones[0].Name != makeDomainCanonical(members[1]) {
+		t.Errorf("unexpected members %+v: %v", zones, err)
+	}
+
+	if _, err := p.Catalog.Members(ctx, "unknown-"+catalog); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := p.Catalog.RemoveMember(ctx, catalog, "unknown-"+catalog); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCatalogMembersError(t *testing.T) {
+	catalog := generateNativeZone(true)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerCatalogMockResponder(catalog)
+
+	listError := errors.New("list failed")
+	p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithMiddleware(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if strings.HasSuffix(req.URL.Path, "/zones") {
+				return nil, listError
+			}
+			return next.Do(req)
+		})
+	}))
+	if _, err := p.Catalog.Members(context.Background(), catalog); !errors.Is(err, listError) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCatalogGroupsError(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("failures are simulated by mocks")
+	}
+	catalog := generateNativeZone(false)
+	member := generateNativeZone(false)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerCatalogMockResponder(member)
+
+	ctx := context.Background()
+	if _, err := initialisePowerDNSTestClient().Catalog.Create(ctx, catalog); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, failingMethod := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		if err := initialisePowerDNSTestClient().Catalog.AddMember(ctx, catalog, member); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		requestError := errors.New("request failed")
+		p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithMiddleware(func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method == failingMethod && (req.Method != http.MethodGet || strings.Contains(req.URL.Path, "/metadata/")) {
+					return nil, requestError
+				}
+				return next.Do(req)
+			})
+		}))
+
+		if _, err := p.Catalog.Groups(ctx, catalog, member); failingMethod == http.MethodGet && !errors.Is(err, requestError) {
+			t.Errorf("%s: unexpected error: %v", failingMethod, err)
+		}
+		if err := p.Catalog.SetGroups(ctx, catalog, member, "signed"); failingMethod == http.MethodPut && !errors.Is(err, requestError) {
+			t.Errorf("%s: unexpected error: %v", failingMethod, err)
+		}
+		if err := p.Catalog.RemoveMember(ctx, catalog, member); failingMethod != http.MethodGet && !errors.Is(err, requestError) {
+			t.Errorf("%s: unexpected error: %v", failingMethod, err)
+		}
+	}
+
+	p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithMiddleware(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("request failed")
+		})
+	}))
+	if err := p.Catalog.SetGroups(ctx, catalog, member); err == nil {
+		t.Error("error is nil")
+	}
+}
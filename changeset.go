@@ -0,0 +1,211 @@
+package powerdns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxChangeSetSize is the default number of RRset changes committed per PATCH request
+const DefaultMaxChangeSetSize = 1000
+
+// ChangeSet accumulates RRset changes of a zone, which are committed together by as few PATCH requests as possible.
+// Changes are validated locally when they are added, so conflicting changes are rejected before anything is sent.
+type ChangeSet struct {
+	service *RecordsService
+	domain  string
+	maxSize int
+	changes []RRset
+	index   map[rrSetKey]int
+}
+
+// ChangeSetOption is a functional option for RecordsService.NewChangeSet.
+type ChangeSetOption func(*ChangeSet)
+
+// WithMaxChangeSetSize limits the number of RRset changes per PATCH request. Larger change sets are split.
+// A size of 0 disables splitting.
+func WithMaxChangeSetSize(size int) ChangeSetOption {
+	return func(c *ChangeSet) {
+		c.maxSize = size
+	}
+}
+
+// NewChangeSet creates an empty change set for domain
+func (r *RecordsService) NewChangeSet(domain string, options ...ChangeSetOption) *ChangeSet {
+	c := &ChangeSet{
+		service: r,
+		domain:  makeDomainCanonical(domain),
+		maxSize: DefaultMaxChangeSetSize,
+		index:   make(map[rrSetKey]int),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Replace adds a change which replaces the records of an RRset, similar to RecordsService.Change.
+// Comments which have been set by SetComments for the same RRset are kept.
+func (c *ChangeSet) Replace(name string, recordType RRType, ttl uint32, content []string, options ...func(*RRset)) error {
+	rrSet := RRset{Name: String(name), Type: &recordType, TTL: &ttl, ChangeType: ChangeTypePtr(ChangeTypeReplace), Records: make([]Record, len(content))}
+	for _, option := range options {
+		option(&rrSet)
+	}
+
+	for i, content := range content {
+		rrSet.Records[i] = Record{Content: String(content), Disabled: Bool(false), SetPTR: Bool(false)}
+	}
+	fixRRSet(&rrSet)
+
+	seen := make(map[string]bool, len(rrSet.Records))
+	for _, record := range rrSet.Records {
+		if seen[*record.Content] {
+			return fmt.Errorf("%w: duplicate content %q in %s %s", ErrChangeSetConflict, *record.Content, name, recordType)
+		}
+		seen[*record.Content] = true
+	}
+
+	key, err := c.validate(&rrSet)
+	if err != nil {
+		return err
+	}
+
+	i, exists := c.index[key]
+	if exists && (c.changes[i].Records != nil || *c.changes[i].ChangeType == ChangeTypeDelete) {
+		return c.conflict(key)
+	}
+	if err := c.validateCNAME(key); err != nil {
+		return err
+	}
+
+	if exists {
+		rrSet.Comments = append(c.changes[i].Comments, rrSet.Comments...)
+		c.changes[i] = rrSet
+		return nil
+	}
+	c.add(key, rrSet)
+	return nil
+}
+
+// Delete adds a change which deletes an RRset, similar to RecordsService.Delete.
+func (c *ChangeSet) Delete(name string, recordType RRType) error {
+	rrSet := RRset{Name: String(name), Type: &recordType, ChangeType: ChangeTypePtr(ChangeTypeDelete)}
+
+	key, err := c.validate(&rrSet)
+	if err != nil {
+		return err
+	}
+	if _, exists := c.index[key]; exists {
+		return c.conflict(key)
+	}
+
+	c.add(key, rrSet)
+	return nil
+}
+
+// SetComments adds a change which replaces the comments of an RRset, including comments which have been set before within the same change set.
+// Without comments, the comments of the RRset are cleared.
+// Unless the records of the RRset are replaced within the same change set, they remain unchanged.
+func (c *ChangeSet) SetComments(name string, recordType RRType, comments ...Comment) error {
+	rrSet := RRset{Name: String(name), Type: &recordType, ChangeType: ChangeTypePtr(ChangeTypeReplace), Comments: append([]Comment{}, comments...)}
+
+	key, err := c.validate(&rrSet)
+	if err != nil {
+		return err
+	}
+
+	if i, exists := c.index[key]; exists {
+		change := &c.changes[i]
+		if *change.ChangeType == ChangeTypeDelete {
+			return c.conflict(key)
+		}
+		change.Comments = append([]Comment{}, comments...)
+		return nil
+	}
+
+	c.add(key, rrSet)
+	return nil
+}
+
+// Len returns the number of RRset changes
+func (c *ChangeSet) Len() int {
+	return len(c.changes)
+}
+
+// RRsets returns the accumulated changes. Deletions are ordered before replacements.
+func (c *ChangeSet) RRsets() *RRsets {
+	deletions := make([]RRset, 0, len(c.changes))
+	replacements := make([]RRset, 0, len(c.changes))
+	for _, change := range c.changes {
+		if *change.ChangeType == ChangeTypeDelete {
+			deletions = append(deletions, change)
+		} else {
+			replacements = append(replacements, change)
+		}
+	}
+	return &RRsets{Sets: append(deletions, replacements...)}
+}
+
+// Commit sends the accumulated changes by RecordsService.Patch, split into several requests if the change set exceeds its maximum size.
+// Deletions are sent first, so an RRset can be replaced by a CNAME even if the change set is split.
+// The change set is empty after a successful commit and can be reused.
+// If a request fails, the changes of previous requests remain applied, and the change set keeps all changes.
+func (c *ChangeSet) Commit(ctx context.Context) error {
+	ctx = withOperation(ctx, Operation{Service: "Records", Method: "Commit", Zone: c.domain})
+
+	sets := c.RRsets().Sets
+	chunkSize := c.maxSize
+	if chunkSize <= 0 {
+		chunkSize = len(sets)
+	}
+
+	for committed := 0; committed < len(sets); committed += chunkSize {
+		chunk := sets[committed:min(committed+chunkSize, len(sets))]
+		if err := c.service.Patch(ctx, c.domain, &RRsets{Sets: chunk}); err != nil {
+			if committed > 0 {
+				return fmt.Errorf("committed %d of %d RRset changes: %w", committed, len(sets), err)
+			}
+			return err
+		}
+	}
+
+	c.changes = nil
+	clear(c.index)
+	return nil
+}
+
+func (c *ChangeSet) add(key rrSetKey, rrSet RRset) {
+	c.index[key] = len(c.changes)
+	c.changes = append(c.changes, rrSet)
+}
+
+func (c *ChangeSet) conflict(key rrSetKey) error {
+	return fmt.Errorf("%w: %s %s has already been changed", ErrChangeSetConflict, key.name, key.recordType)
+}
+
+// validate canonicalises the name of rrSet and makes sure that it belongs to the zone.
+func (c *ChangeSet) validate(rrSet *RRset) (rrSetKey, error) {
+	rrSet.Name = String(makeDomainCanonical(*rrSet.Name))
+	key := newRRSetKey(rrSet)
+
+	zone := strings.ToLower(c.domain)
+	if key.name != zone && !strings.HasSuffix(key.name, "."+zone) {
+		return key, fmt.Errorf("%w: %s is not within zone %s", ErrChangeSetConflict, key.name, c.domain)
+	}
+
+	return key, nil
+}
+
+// validateCNAME rejects replacing a CNAME next to other replaced RRsets of the same name.
+func (c *ChangeSet) validateCNAME(key rrSetKey) error {
+	for other, i := range c.index {
+		change := c.changes[i]
+		if other.name != key.name || change.Records == nil || *change.ChangeType != ChangeTypeReplace {
+			continue
+		}
+		if (key.recordType == RRTypeCNAME) != (other.recordType == RRTypeCNAME) {
+			return fmt.Errorf("%w: %s %s conflicts with %s %s", ErrChangeSetConflict, key.name, key.recordType, other.name, other.recordType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package powerdns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func generateTestChangeSet(t *testing.T, p *Client, domain string, options ...ChangeSetOption) *ChangeSet {
+	t.Helper()
+
+	changeSet := p.Records.NewChangeSet(domain, options...)
+	if err := changeSet.Replace("www."+domain, RRTypeA, 300, []string{"192.0.2.1", "192.0.2.2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := changeSet.Delete("old."+domain, RRTypeTXT); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := changeSet.SetComments(domain, RRTypeMX, Comment{Content: String("mail"), Account: String("ops")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := changeSet.Replace("alias."+domain, RRTypeCNAME, 300, []string{"www." + domain}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return changeSet
+}
+
+func TestChangeSetRRsets(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	changeSet := generateTestChangeSet(t, p, "example.com")
+
+	if changeSet.Len() != 4 {
+		t.Errorf("unexpected length %d", changeSet.Len())
+	}
+
+	sets := changeSet.RRsets().Sets
+	if *sets[0].ChangeType != ChangeTypeDelete || *sets[0].Name != "old.example.com." {
+		t.Errorf("deletions are not ordered first: %+v", sets[0])
+	}
+	if *sets[1].Name != "www.example.com." || len(sets[1].Records) != 2 {
+		t.Errorf("unexpected replacement %+v", sets[1])
+	}
+	if sets[2].Records != nil || len(sets[2].Comments) != 1 {
+		t.Errorf("comment-only change replaces records: %+v", sets[2])
+	}
+	if *sets[3].Records[0].Content != "www.example.com." {
+		t.Errorf("CNAME content has not been canonicalised: %s", *sets[3].Records[0].Content)
+	}
+}
+
+func TestChangeSetCommit(t *testing.T) {
+	testCases := []struct {
+		maxSize     int
+		wantPatches int
+	}{
+		{0, 1},
+		{1, 4},
+		{3, 2},
+		{DefaultMaxChangeSetSize, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			testDomain := generateNativeZone(true)
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			rrSets := generateTestRecordStore(testDomain)
+			registerRecordStoreMockResponder(testDomain, &rrSets, &[]RRsets{}, func(int) *http.Response { return nil })
+
+			var patches []RRsets
+			p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithMiddleware(recordPayloads(http.MethodPatch, &patches)))
+			changeSet := generateTestChangeSet(t, p, testDomain, WithMaxChangeSetSize(tc.maxSize))
+			if err := changeSet.Commit(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(patches) != tc.wantPatches {
+				t.Fatalf("unexpected number of patches: %d", len(patches))
+			}
+			if *patches[0].Sets[0].ChangeType != ChangeTypeDelete {
+				t.Error("deletions have not been sent first")
+			}
+
+			if err := changeSet.Commit(context.Background()); err != nil || changeSet.Len() != 0 || len(patches) != tc.wantPatches {
+				t.Errorf("the change set has not been reset: %v %d", err, len(patches))
+			}
+			if err := changeSet.Delete("www."+testDomain, RRTypeA); err != nil {
+				t.Errorf("the change set cannot be reused: %v", err)
+			}
+		})
+	}
+}
+
+func TestChangeSetCommitEmpty(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	if err := p.Records.NewChangeSet("example.com", WithMaxChangeSetSize(0)).Commit(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestChangeSetCommitError(t *testing.T) {
+	for _, failingPatch := range []int{1, 2} {
+		t.Run("", func(t *testing.T) {
+			testDomain := generateNativeZone(true)
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			rrSets := generateTestRecordStore(testDomain)
+			registerRecordStoreMockResponder(testDomain, &rrSets, &[]RRsets{}, func(int) *http.Response { return nil })
+
+			// The failing patch is rejected before it reaches the server, so that the error does not depend on the server
+			patchCount := 0
+			p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithMiddleware(func(next Doer) Doer {
+				return DoerFunc(func(req *http.Request) (*http.Response, error) {
+					if req.Method == http.MethodPatch {
+						if patchCount++; patchCount == failingPatch {
+							return httpmock.NewJsonResponse(http.StatusUnprocessableEntity, Error{Message: "RRset www.example.com. IN CNAME: Conflicts with pre-existing RRset"})
+						}
+					}
+					return next.Do(req)
+				})
+			}))
+			changeSet := generateTestChangeSet(t, p, testDomain, WithMaxChangeSetSize(2))
+			err := changeSet.Commit(context.Background())
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if failingPatch == 2 && err.Error() != "committed 2 of 4 RRset changes: RRset www.example.com. IN CNAME: Conflicts with pre-existing RRset" {
+				t.Errorf("unexpected error message: %v", err)
+			}
+		})
+	}
+}
+
+func TestChangeSetComments(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	changeSet := p.Records.NewChangeSet("example.com")
+
+	if err := changeSet.SetComments("www.example.com", RRTypeA, Comment{Content: String("first")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := changeSet.Replace("www.example.com", RRTypeA, 300, []string{"192.0.2.1"}, WithComments(Comment{Content: String("second")})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sets := changeSet.RRsets().Sets
+	if len(sets) != 1 || len(sets[0].Records) != 1 || len(sets[0].Comments) != 2 {
+		t.Errorf("unexpected changes %+v", sets)
+	}
+
+	for _, content := range []string{"third", "fourth"} {
+		if err := changeSet.SetComments("WWW.example.com.", RRTypeA, Comment{Content: String(content)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	sets = changeSet.RRsets().Sets
+	if len(sets) != 1 || len(sets[0].Records) != 1 || len(sets[0].Comments) != 1 || *sets[0].Comments[0].Content != "fourth" {
+		t.Errorf("unexpected changes %+v", sets)
+	}
+}
+
+func TestChangeSetClearComments(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	changeSet := p.Records.NewChangeSet("example.com")
+
+	if err := changeSet.SetComments("www.example.com", RRTypeA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := changeSet.Replace("mail.example.com", RRTypeA, 300, []string{"192.0.2.1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := changeSet.SetComments("mail.example.com", RRTypeA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := changeSet.Replace("ftp.example.com", RRTypeA, 300, []string{"192.0.2.1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := changeSet.Replace("smtp.example.com", RRTypeA, 300, []string{"192.0.2.1"}, WithComments(Comment{Content: String("mail")})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := changeSet.SetComments("smtp.example.com", RRTypeA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	payload, err := json.Marshal(changeSet.RRsets())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(string(payload), `"comments":[]`) != 3 || strings.Count(string(payload), `"comments"`) != 3 {
+		t.Errorf("unexpected payload %s", payload)
+	}
+}
+
+func TestChangeSetConflicts(t *testing.T) {
+	testCases := []struct {
+		name   string
+		change func(c *ChangeSet) error
+	}{
+		{"duplicate replace", func(c *ChangeSet) error { return c.Replace("www.example.com.", RRTypeA, 60, []string{"192.0.2.3"}) }},
+		{"delete after replace", func(c *ChangeSet) error { return c.Delete("www.example.com", RRTypeA) }},
+		{"replace after delete", func(c *ChangeSet) error { return c.Replace("old.example.com", RRTypeTXT, 60, []string{`"foo"`}) }},
+		{"comments after delete", func(c *ChangeSet) error { return c.SetComments("old.example.com", RRTypeTXT) }},
+		{"duplicate content", func(c *ChangeSet) error {
+			return c.Replace("new.example.com", RRTypeNS, 60, []string{"ns.example.com", "ns.example.com."})
+		}},
+		{"outside zone", func(c *ChangeSet) error { return c.Delete("example.org", RRTypeA) }},
+		{"suffix outside zone", func(c *ChangeSet) error { return c.Delete("notexample.com", RRTypeA) }},
+		{"replace outside zone", func(c *ChangeSet) error { return c.Replace("example.org", RRTypeA, 60, nil) }},
+		{"comments outside zone", func(c *ChangeSet) error { return c.SetComments("example.org", RRTypeA) }},
+		{"CNAME next to data", func(c *ChangeSet) error {
+			return c.Replace("www.example.com", RRTypeCNAME, 60, []string{"example.com"})
+		}},
+		{"data next to CNAME", func(c *ChangeSet) error { return c.Replace("alias.example.com", RRTypeTXT, 60, []string{`"foo"`}) }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := initialisePowerDNSTestClient()
+			changeSet := generateTestChangeSet(t, p, "example.com")
+			if err := tc.change(changeSet); !errors.Is(err, ErrChangeSetConflict) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if changeSet.Len() != 4 {
+				t.Errorf("a conflicting change has been added")
+			}
+		})
+	}
+}
+
+func TestChangeSetCNAMEAfterComments(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	changeSet := generateTestChangeSet(t, p, "example.com")
+
+	if err := changeSet.Replace("example.com", RRTypeA, 60, []string{"192.0.2.1"}); err != nil {
+		t.Errorf("comment-only change conflicts with data: %v", err)
+	}
+	if err := changeSet.SetComments("alias.example.com", RRTypeTXT); err != nil {
+		t.Errorf("comment-only change conflicts with CNAME: %v", err)
+	}
+	if err := changeSet.Replace("alias.example.com", RRTypeTXT, 60, []string{`"foo"`}); !errors.Is(err, ErrChangeSetConflict) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -1,9 +1,15 @@
 package powerdns
 
 import (
+	"cmp"
 	"context"
+	"fmt"
 	"net/http"
 	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // ConfigService handles communication with the zones related methods of the Client API
@@ -16,14 +22,145 @@ type ConfigSetting struct {
 	Value *string `json:"value,omitempty"`
 }
 
+// ConfigSettings is the configuration of a server, which offers typed access to its settings
+type ConfigSettings []ConfigSetting
+
+// ConfigDifference is a setting whose value differs between two configurations.
+// A value is nil if the setting is missing in the respective configuration.
+type ConfigDifference struct {
+	Name       string
+	Value      *string
+	OtherValue *string
+}
+
 // List retrieves a list of ConfigSettings
-func (c *ConfigService) List(ctx context.Context) ([]ConfigSetting, error) {
+func (c *ConfigService) List(ctx context.Context) (ConfigSettings, error) {
+	ctx = withOperation(ctx, Operation{Service: "Config", Method: "List"})
+
 	req, err := c.client.newRequest(ctx, http.MethodGet, path.Join("servers", c.client.VHost, "config"), nil, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	config := make([]ConfigSetting, 0)
+	config := make(ConfigSettings, 0)
 	_, err = c.client.do(req, &config)
 	return config, err
 }
+
+// Diff retrieves the configurations of this and the other server and returns their differences.
+// Settings which are expected to differ, e.g. server-id, can be ignored.
+func (c *ConfigService) Diff(ctx context.Context, other *Client, ignore ...string) ([]ConfigDifference, error) {
+	ctx = withOperation(ctx, Operation{Service: "Config", Method: "Diff"})
+
+	config, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	otherConfig, err := other.Config.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return config.Diff(otherConfig, ignore...), nil
+}
+
+// Lookup returns the value of the setting with the given name, and whether it exists
+func (c ConfigSettings) Lookup(name string) (string, bool) {
+	for _, setting := range c {
+		if StringValue(setting.Name) == name {
+			return StringValue(setting.Value), true
+		}
+	}
+	return "", false
+}
+
+func (c ConfigSettings) lookup(name string) (string, error) {
+	value, ok := c.Lookup(name)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSettingNotFound, name)
+	}
+	return value, nil
+}
+
+// Bool parses the value of a boolean setting, which PowerDNS represents as yes or no
+func (c ConfigSettings) Bool(name string) (bool, error) {
+	value, err := c.lookup(name)
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(value) {
+	case "yes", "on", "true", "1":
+		return true, nil
+	case "no", "off", "false", "0", "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("setting %s: invalid boolean %q", name, value)
+	}
+}
+
+// Int parses the value of an integer setting
+func (c ConfigSettings) Int(name string) (int64, error) {
+	value, err := c.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("setting %s: %w", name, err)
+	}
+	return i, nil
+}
+
+// Duration parses the value of an integer setting, which is a duration measured in unit, e.g. time.Second for default-ttl
+func (c ConfigSettings) Duration(name string, unit time.Duration) (time.Duration, error) {
+	i, err := c.Int(name)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(i) * unit, nil
+}
+
+// List splits the value of a list setting, whose items are separated by commas or whitespace
+func (c ConfigSettings) List(name string) ([]string, error) {
+	value, err := c.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	}), nil
+}
+
+// Diff returns the settings whose values differ from the other configuration, ordered by name.
+// Settings which are expected to differ, e.g. server-id, can be ignored.
+func (c ConfigSettings) Diff(other ConfigSettings, ignore ...string) []ConfigDifference {
+	differences := make([]ConfigDifference, 0)
+	add := func(name string, value, otherValue *string) {
+		if !slices.Contains(ignore, name) {
+			differences = append(differences, ConfigDifference{Name: name, Value: value, OtherValue: otherValue})
+		}
+	}
+
+	for _, setting := range c {
+		name := StringValue(setting.Name)
+		otherValue, ok := other.Lookup(name)
+		switch {
+		case !ok:
+			add(name, setting.Value, nil)
+		case otherValue != StringValue(setting.Value):
+			add(name, setting.Value, String(otherValue))
+		}
+	}
+	for _, setting := range other {
+		if _, ok := c.Lookup(StringValue(setting.Name)); !ok {
+			add(StringValue(setting.Name), nil, setting.Value)
+		}
+	}
+
+	slices.SortFunc(differences, func(a, b ConfigDifference) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return differences
+}
@@ -2,8 +2,12 @@ package powerdns
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"reflect"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/jarcoal/httpmock"
 )
@@ -49,3 +53,99 @@ func TestListConfigError(t *testing.T) {
 		t.Error("error is nil")
 	}
 }
+
+func testConfigSettings(pairs ...string) ConfigSettings {
+	settings := make(ConfigSettings, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		settings = append(settings, ConfigSetting{Name: String(pairs[i]), Type: String("ConfigSetting"), Value: String(pairs[i+1])})
+	}
+	return settings
+}
+
+func TestConfigSettingsTypedValues(t *testing.T) {
+	settings := testConfigSettings("api", "yes", "disable-axfr", "no", "dnssec", "maybe", "signing-threads", "3", "default-ttl", "3600", "allow-axfr-ips", "127.0.0.0/8, ::1 192.0.2.0/24", "only-notify", "")
+
+	if value, ok := settings.Lookup("signing-threads"); !ok || value != "3" {
+		t.Errorf("unexpected value %q %v", value, ok)
+	}
+	if _, ok := settings.Lookup("unknown"); ok {
+		t.Error("unknown setting found")
+	}
+
+	if value, err := settings.Bool("api"); err != nil || !value {
+		t.Errorf("unexpected value %v: %v", value, err)
+	}
+	if value, err := settings.Bool("disable-axfr"); err != nil || value {
+		t.Errorf("unexpected value %v: %v", value, err)
+	}
+	if _, err := settings.Bool("dnssec"); err == nil {
+		t.Error("error is nil")
+	}
+	if _, err := settings.Bool("unknown"); !errors.Is(err, ErrSettingNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if value, err := settings.Int("signing-threads"); err != nil || value != 3 {
+		t.Errorf("unexpected value %d: %v", value, err)
+	}
+	if _, err := settings.Int("api"); err == nil {
+		t.Error("error is nil")
+	}
+
+	if value, err := settings.Duration("default-ttl", time.Second); err != nil || value != time.Hour {
+		t.Errorf("unexpected value %s: %v", value, err)
+	}
+	if _, err := settings.Duration("unknown", time.Second); !errors.Is(err, ErrSettingNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if value, err := settings.List("allow-axfr-ips"); err != nil || !slices.Equal(value, []string{"127.0.0.0/8", "::1", "192.0.2.0/24"}) {
+		t.Errorf("unexpected value %v: %v", value, err)
+	}
+	if value, err := settings.List("only-notify"); err != nil || len(value) != 0 {
+		t.Errorf("unexpected value %v: %v", value, err)
+	}
+	if _, err := settings.List("unknown"); !errors.Is(err, ErrSettingNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestConfigSettingsDiff(t *testing.T) {
+	primary := testConfigSettings("server-id", "ns1", "default-ttl", "3600", "primary", "yes", "signing-threads", "3")
+	secondary := testConfigSettings("server-id", "ns2", "default-ttl", "3600", "secondary", "yes", "signing-threads", "4")
+
+	differences := primary.Diff(secondary, "server-id")
+	want := []ConfigDifference{
+		{Name: "primary", Value: String("yes")},
+		{Name: "secondary", OtherValue: String("yes")},
+		{Name: "signing-threads", Value: String("3"), OtherValue: String("4")},
+	}
+	if !reflect.DeepEqual(differences, want) {
+		t.Errorf("unexpected differences %+v", differences)
+	}
+
+	if differences := primary.Diff(primary); len(differences) != 0 {
+		t.Errorf("unexpected differences %+v", differences)
+	}
+}
+
+func TestDiffConfig(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerConfigsMockResponder()
+
+	p := initialisePowerDNSTestClient()
+	differences, err := p.Config.Diff(context.Background(), p)
+	if err != nil || len(differences) != 0 {
+		t.Errorf("unexpected differences %+v: %v", differences, err)
+	}
+
+	other := initialisePowerDNSTestClient()
+	other.BaseURL = "://"
+	if _, err := p.Config.Diff(context.Background(), other); err == nil {
+		t.Error("error is nil")
+	}
+	if _, err := other.Config.Diff(context.Background(), p); err == nil {
+		t.Error("error is nil")
+	}
+}
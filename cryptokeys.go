@@ -16,6 +16,7 @@ type Cryptokey struct {
 	ID         *uint64  `json:"id,omitempty"`
 	KeyType    *string  `json:"keytype,omitempty"`
 	Active     *bool    `json:"active,omitempty"`
+	Published  *bool    `json:"published,omitempty"`
 	DNSkey     *string  `json:"dnskey,omitempty"`
 	DS         []string `json:"ds,omitempty"`
 	Privatekey *string  `json:"privatekey,omitempty"`
@@ -29,7 +30,9 @@ func cryptokeyIDToString(id uint64) string {
 
 // List retrieves a list of Cryptokeys that belong to a Zone
 func (c *CryptokeysService) List(ctx context.Context, domain string) ([]Cryptokey, error) {
-	req, err := c.client.newRequest(ctx, http.MethodGet, path.Join("servers", c.client.VHost, "zones", makeDomainCanonical(domain), "cryptokeys"), nil, nil)
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "List", Zone: makeDomainCanonical(domain)})
+
+	req, err := c.client.newRequest(ctx, http.MethodGet, path.Join("servers", c.client.VHost, "zones", zoneID(domain), "cryptokeys"), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +44,9 @@ func (c *CryptokeysService) List(ctx context.Context, domain string) ([]Cryptoke
 
 // Get returns a certain Cryptokey instance of a given Zone
 func (c *CryptokeysService) Get(ctx context.Context, domain string, id uint64) (*Cryptokey, error) {
-	req, err := c.client.newRequest(ctx, http.MethodGet, path.Join("servers", c.client.VHost, "zones", makeDomainCanonical(domain), "cryptokeys", cryptokeyIDToString(id)), nil, nil)
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "Get", Zone: makeDomainCanonical(domain)})
+
+	req, err := c.client.newRequest(ctx, http.MethodGet, path.Join("servers", c.client.VHost, "zones", zoneID(domain), "cryptokeys", cryptokeyIDToString(id)), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -51,9 +56,88 @@ func (c *CryptokeysService) Get(ctx context.Context, domain string, id uint64) (
 	return cryptokey, err
 }
 
+// Create adds a Cryptokey to a given Zone.
+// PowerDNS generates the key pair from KeyType, Algorithm and Bits, unless Privatekey provides a key in ISC format to import.
+func (c *CryptokeysService) Create(ctx context.Context, domain string, cryptokey *Cryptokey) (*Cryptokey, error) {
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "Create", Zone: makeDomainCanonical(domain)})
+
+	req, err := c.client.newRequest(ctx, http.MethodPost, path.Join("servers", c.client.VHost, "zones", zoneID(domain), "cryptokeys"), nil, cryptokey)
+	if err != nil {
+		return nil, err
+	}
+
+	createdCryptokey := new(Cryptokey)
+	_, err = c.client.do(req, &createdCryptokey)
+	return createdCryptokey, err
+}
+
+// Activate makes a given Cryptokey sign the Zone
+func (c *CryptokeysService) Activate(ctx context.Context, domain string, id uint64) error {
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "Activate", Zone: makeDomainCanonical(domain)})
+
+	return c.update(ctx, domain, id, func(cryptokey *Cryptokey) {
+		cryptokey.Active = Bool(true)
+	})
+}
+
+// Deactivate stops a given Cryptokey from signing the Zone
+func (c *CryptokeysService) Deactivate(ctx context.Context, domain string, id uint64) error {
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "Deactivate", Zone: makeDomainCanonical(domain)})
+
+	return c.update(ctx, domain, id, func(cryptokey *Cryptokey) {
+		cryptokey.Active = Bool(false)
+	})
+}
+
+// Publish adds the DNSKEY record of a given Cryptokey to the Zone
+func (c *CryptokeysService) Publish(ctx context.Context, domain string, id uint64) error {
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "Publish", Zone: makeDomainCanonical(domain)})
+
+	return c.update(ctx, domain, id, func(cryptokey *Cryptokey) {
+		cryptokey.Published = Bool(true)
+	})
+}
+
+// Unpublish removes the DNSKEY record of a given Cryptokey from the Zone
+func (c *CryptokeysService) Unpublish(ctx context.Context, domain string, id uint64) error {
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "Unpublish", Zone: makeDomainCanonical(domain)})
+
+	return c.update(ctx, domain, id, func(cryptokey *Cryptokey) {
+		cryptokey.Published = Bool(false)
+	})
+}
+
+// Change sets the active and published flags of a given Cryptokey.
+// PowerDNS requires Active to be set and publishes the key if Published is nil.
+func (c *CryptokeysService) Change(ctx context.Context, domain string, id uint64, cryptokey *Cryptokey) error {
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "Change", Zone: makeDomainCanonical(domain)})
+
+	req, err := c.client.newRequest(ctx, http.MethodPut, path.Join("servers", c.client.VHost, "zones", zoneID(domain), "cryptokeys", cryptokeyIDToString(id)), nil, cryptokey)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.do(req, nil)
+	return err
+}
+
+// update changes the flags of a Cryptokey, based on its current state so that the other flag is kept
+func (c *CryptokeysService) update(ctx context.Context, domain string, id uint64, change func(*Cryptokey)) error {
+	current, err := c.Get(ctx, domain, id)
+	if err != nil {
+		return err
+	}
+
+	cryptokey := &Cryptokey{Active: Bool(BoolValue(current.Active)), Published: current.Published}
+	change(cryptokey)
+	return c.Change(ctx, domain, id, cryptokey)
+}
+
 // Delete removes a given Cryptokey
 func (c *CryptokeysService) Delete(ctx context.Context, domain string, id uint64) error {
-	req, err := c.client.newRequest(ctx, http.MethodDelete, path.Join("servers", c.client.VHost, "zones", makeDomainCanonical(domain), "cryptokeys", cryptokeyIDToString(id)), nil, nil)
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "Delete", Zone: makeDomainCanonical(domain)})
+
+	req, err := c.client.newRequest(ctx, http.MethodDelete, path.Join("servers", c.client.VHost, "zones", zoneID(domain), "cryptokeys", cryptokeyIDToString(id)), nil, nil)
 	if err != nil {
 		return err
 	}
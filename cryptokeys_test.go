@@ -2,8 +2,10 @@ package powerdns
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -173,3 +175,189 @@ func TestDeleteCryptokeyError(t *testing.T) {
 		t.Error("error is nil")
 	}
 }
+
+// registerCryptokeyChangeMockResponder serves a single cryptokey, which is created by POST and changed by PUT like PowerDNS does
+func registerCryptokeyChangeMockResponder(testDomain string) {
+	var current *Cryptokey
+	cryptokeysURL := generateTestAPIVHostURL() + "/zones/" + makeDomainCanonical(testDomain) + "/cryptokeys"
+
+	httpmock.RegisterResponder("POST", cryptokeysURL,
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			var cryptokey Cryptokey
+			if json.NewDecoder(req.Body).Decode(&cryptokey) != nil {
+				return httpmock.NewBytesResponse(http.StatusBadRequest, []byte{}), nil
+			}
+			current = &Cryptokey{Type: String("Cryptokey"), ID: Uint64(10), KeyType: cryptokey.KeyType, Active: Bool(BoolValue(cryptokey.Active)), Published: Bool(cryptokey.Published == nil || *cryptokey.Published)}
+			return httpmock.NewJsonResponse(http.StatusCreated, current)
+		},
+	)
+
+	httpmock.RegisterResponder("GET", cryptokeysURL+"/10",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, current)
+		},
+	)
+
+	httpmock.RegisterResponder("PUT", cryptokeysURL+"/10",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			var change Cryptokey
+			if json.NewDecoder(req.Body).Decode(&change) != nil || change.Active == nil {
+				return httpmock.NewJsonResponse(http.StatusUnprocessableEntity, Error{Message: "Invalid request: 'active' must be a boolean"})
+			}
+			current.Active = change.Active
+			current.Published = Bool(change.Published == nil || *change.Published)
+			return httpmock.NewStringResponse(http.StatusNoContent, ""), nil
+		},
+	)
+}
+
+func TestCreateCryptokey(t *testing.T) {
+	testDomain := generateNativeZone(true)
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", generateTestAPIVHostURL()+"/zones/"+makeDomainCanonical(testDomain)+"/cryptokeys",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			var cryptokey Cryptokey
+			if json.NewDecoder(req.Body).Decode(&cryptokey) != nil || *cryptokey.KeyType != "csk" {
+				return httpmock.NewBytesResponse(http.StatusBadRequest, []byte{}), nil
+			}
+
+			cryptokey.Type = String("Cryptokey")
+			cryptokey.ID = Uint64(42)
+			cryptokey.DNSkey = String("257 3 13 thisIsTheKey")
+			return httpmock.NewJsonResponse(http.StatusCreated, cryptokey)
+		},
+	)
+
+	p := initialisePowerDNSTestClient()
+	cryptokey, err := p.Cryptokeys.Create(context.Background(), testDomain, &Cryptokey{KeyType: String("csk"), Active: Bool(true), Published: Bool(false), Algorithm: String("ecdsa256")})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if cryptokey.ID == nil || *cryptokey.ID == 0 || !*cryptokey.Active || *cryptokey.Published || !strings.HasPrefix(StringValue(cryptokey.DNSkey), "257 3 13 ") {
+		t.Errorf("Created cryptokey is wrong: %+v", cryptokey)
+	}
+}
+
+func TestCreateCryptokeyError(t *testing.T) {
+	testDomain := generateNativeZone(false)
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if _, err := p.Cryptokeys.Create(context.Background(), testDomain, &Cryptokey{KeyType: String("ksk")}); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestChangeCryptokey(t *testing.T) {
+	testDomain := generateNativeZone(true)
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerCryptokeyChangeMockResponder(testDomain)
+
+	p := initialisePowerDNSTestClient()
+	created, err := p.Cryptokeys.Create(context.Background(), testDomain, &Cryptokey{KeyType: String("csk"), Active: Bool(false), Published: Bool(false)})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if err := p.Cryptokeys.Change(context.Background(), testDomain, *created.ID, &Cryptokey{Active: Bool(true)}); err != nil {
+		t.Errorf("%s", err)
+	}
+	cryptokey, err := p.Cryptokeys.Get(context.Background(), testDomain, *created.ID)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !*cryptokey.Active || !*cryptokey.Published {
+		t.Errorf("Unexpected cryptokey: %+v", cryptokey)
+	}
+
+	if err := p.Cryptokeys.Change(context.Background(), testDomain, *created.ID, &Cryptokey{Published: Bool(true)}); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestChangeCryptokeyError(t *testing.T) {
+	testDomain := generateNativeZone(false)
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if err := p.Cryptokeys.Change(context.Background(), testDomain, 10, &Cryptokey{Active: Bool(true)}); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestCryptokeyStateChanges(t *testing.T) {
+	testCases := []struct {
+		name          string
+		current       Cryptokey
+		change        func(p *Client, domain string, id uint64) error
+		wantActive    bool
+		wantPublished bool
+	}{
+		{"activate", Cryptokey{Active: Bool(false), Published: Bool(false)}, func(p *Client, domain string, id uint64) error {
+			return p.Cryptokeys.Activate(context.Background(), domain, id)
+		}, true, false},
+		{"deactivate", Cryptokey{Active: Bool(true), Published: Bool(true)}, func(p *Client, domain string, id uint64) error {
+			return p.Cryptokeys.Deactivate(context.Background(), domain, id)
+		}, false, true},
+		{"publish", Cryptokey{Active: Bool(true), Published: Bool(false)}, func(p *Client, domain string, id uint64) error {
+			return p.Cryptokeys.Publish(context.Background(), domain, id)
+		}, true, true},
+		{"unpublish", Cryptokey{Active: Bool(false), Published: Bool(true)}, func(p *Client, domain string, id uint64) error {
+			return p.Cryptokeys.Unpublish(context.Background(), domain, id)
+		}, false, false},
+		{"activate without flags", Cryptokey{}, func(p *Client, domain string, id uint64) error {
+			return p.Cryptokeys.Activate(context.Background(), domain, id)
+		}, true, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testDomain := generateNativeZone(true)
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			registerCryptokeyChangeMockResponder(testDomain)
+
+			p := initialisePowerDNSTestClient()
+			created, err := p.Cryptokeys.Create(context.Background(), testDomain, &Cryptokey{KeyType: String("csk"), Active: tc.current.Active, Published: tc.current.Published})
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			if err := tc.change(p, testDomain, *created.ID); err != nil {
+				t.Errorf("%s", err)
+			}
+
+			cryptokey, err := p.Cryptokeys.Get(context.Background(), testDomain, *created.ID)
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			if *cryptokey.Active != tc.wantActive || *cryptokey.Published != tc.wantPublished {
+				t.Errorf("Unexpected cryptokey: %+v", cryptokey)
+			}
+		})
+	}
+}
+
+func TestCryptokeyStateChangeError(t *testing.T) {
+	testDomain := generateNativeZone(false)
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if err := p.Cryptokeys.Activate(context.Background(), testDomain, 10); err == nil {
+		t.Error("error is nil")
+	}
+}
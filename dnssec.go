@@ -0,0 +1,286 @@
+package powerdns
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+const (
+	// DNSKEYFlagZone is set for keys which sign the zone
+	DNSKEYFlagZone uint16 = 0x0100
+	// DNSKEYFlagSEP is set for key signing keys, which are referenced by DS records
+	DNSKEYFlagSEP uint16 = 0x0001
+)
+
+const (
+	// DigestTypeSHA1 represents DS records with a SHA-1 digest
+	DigestTypeSHA1 uint8 = 1
+	// DigestTypeSHA256 represents DS records with a SHA-256 digest
+	DigestTypeSHA256 uint8 = 2
+	// DigestTypeSHA384 represents DS records with a SHA-384 digest
+	DigestTypeSHA384 uint8 = 4
+)
+
+// DNSKEYData represents the content of a DNSKEY record
+type DNSKEYData struct {
+	Flags     uint16
+	Protocol  uint8
+	Algorithm uint8
+	PublicKey []byte
+}
+
+// RRType returns RRTypeDNSKEY.
+func (d DNSKEYData) RRType() RRType {
+	return RRTypeDNSKEY
+}
+
+func (d DNSKEYData) String() string {
+	return fmt.Sprintf("%d %d %d %s", d.Flags, d.Protocol, d.Algorithm, base64.StdEncoding.EncodeToString(d.PublicKey))
+}
+
+// ParseDNSKEY parses the content of a DNSKEY record.
+func ParseDNSKEY(content string) (DNSKEYData, error) {
+	fields, err := splitRecordContent(RRTypeDNSKEY, content, -4)
+	if err != nil {
+		return DNSKEYData{}, err
+	}
+
+	var d DNSKEYData
+	if d.Flags, err = parseRecordUint[uint16](RRTypeDNSKEY, "flags", fields[0]); err != nil {
+		return DNSKEYData{}, err
+	}
+	if d.Protocol, err = parseRecordUint[uint8](RRTypeDNSKEY, "protocol", fields[1]); err != nil {
+		return DNSKEYData{}, err
+	}
+	if d.Algorithm, err = parseRecordUint[uint8](RRTypeDNSKEY, "algorithm", fields[2]); err != nil {
+		return DNSKEYData{}, err
+	}
+
+	var publicKey strings.Builder
+	for _, field := range fields[3:] {
+		publicKey.WriteString(field.value)
+	}
+	if d.PublicKey, err = base64.StdEncoding.DecodeString(publicKey.String()); err != nil {
+		return DNSKEYData{}, fmt.Errorf("invalid %s public key %q", RRTypeDNSKEY, publicKey.String())
+	}
+
+	return d, nil
+}
+
+// rdata returns the wire format of the record data.
+func (d DNSKEYData) rdata() []byte {
+	rdata := binary.BigEndian.AppendUint16(nil, d.Flags)
+	rdata = append(rdata, d.Protocol, d.Algorithm)
+	return append(rdata, d.PublicKey...)
+}
+
+// KeyTag calculates the key tag according to RFC 4034, appendix B.
+func (d DNSKEYData) KeyTag() uint16 {
+	rdata := d.rdata()
+
+	// Algorithm 1 (RSA/MD5) uses the most significant 16 bits of the least significant 24 bits of the modulus
+	if d.Algorithm == 1 {
+		return binary.BigEndian.Uint16(rdata[len(rdata)-3:])
+	}
+
+	var tag uint32
+	for i, b := range rdata {
+		if i%2 == 0 {
+			tag += uint32(b) << 8
+		} else {
+			tag += uint32(b)
+		}
+	}
+	tag += tag >> 16 & 0xffff
+	return uint16(tag)
+}
+
+// DS calculates the DS record of the key for the zone, using the given digest type.
+func (d DNSKEYData) DS(zone string, digestType uint8) (DSData, error) {
+	var h hash.Hash
+	switch digestType {
+	case DigestTypeSHA1:
+		h = sha1.New()
+	case DigestTypeSHA256:
+		h = sha256.New()
+	case DigestTypeSHA384:
+		h = sha512.New384()
+	default:
+		return DSData{}, fmt.Errorf("unsupported DS digest type %d", digestType)
+	}
+
+	h.Write(canonicalWireName(zone))
+	h.Write(d.rdata())
+	return DSData{KeyTag: d.KeyTag(), Algorithm: d.Algorithm, DigestType: digestType, Digest: h.Sum(nil)}, nil
+}
+
+// canonicalWireName returns the lowercase wire format of a domain name. The variant of a zone variant ID is ignored.
+func canonicalWireName(name string) []byte {
+	name, _ = SplitZoneVariant(strings.ToLower(name))
+	name = strings.TrimSuffix(name, ".")
+
+	var wire []byte
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			wire = append(wire, byte(len(label)))
+			wire = append(wire, label...)
+		}
+	}
+	return append(wire, 0)
+}
+
+// DSData represents the content of a DS record
+type DSData struct {
+	KeyTag     uint16
+	Algorithm  uint8
+	DigestType uint8
+	Digest     []byte
+}
+
+// RRType returns RRTypeDS.
+func (d DSData) RRType() RRType {
+	return RRTypeDS
+}
+
+func (d DSData) String() string {
+	return fmt.Sprintf("%d %d %d %x", d.KeyTag, d.Algorithm, d.DigestType, d.Digest)
+}
+
+// Equal reports whether d and other reference the same key with the same digest.
+func (d DSData) Equal(other DSData) bool {
+	return d.KeyTag == other.KeyTag && d.Algorithm == other.Algorithm && d.DigestType == other.DigestType && bytes.Equal(d.Digest, other.Digest)
+}
+
+// ParseDS parses the content of a DS record.
+func ParseDS(content string) (DSData, error) {
+	fields, err := splitRecordContent(RRTypeDS, content, -4)
+	if err != nil {
+		return DSData{}, err
+	}
+
+	var d DSData
+	if d.KeyTag, err = parseRecordUint[uint16](RRTypeDS, "key tag", fields[0]); err != nil {
+		return DSData{}, err
+	}
+	if d.Algorithm, err = parseRecordUint[uint8](RRTypeDS, "algorithm", fields[1]); err != nil {
+		return DSData{}, err
+	}
+	if d.DigestType, err = parseRecordUint[uint8](RRTypeDS, "digest type", fields[2]); err != nil {
+		return DSData{}, err
+	}
+	if d.Digest, err = parseRecordHex(RRTypeDS, "digest", fields[3:]); err != nil {
+		return DSData{}, err
+	}
+
+	return d, nil
+}
+
+// ParseDNSKEY parses the DNSKEY of the Cryptokey.
+func (c *Cryptokey) ParseDNSKEY() (DNSKEYData, error) {
+	return ParseDNSKEY(StringValue(c.DNSkey))
+}
+
+// ParseDS parses the DS records which PowerDNS reports for the Cryptokey.
+func (c *Cryptokey) ParseDS() ([]DSData, error) {
+	ds := make([]DSData, len(c.DS))
+	for i, content := range c.DS {
+		var err error
+		if ds[i], err = ParseDS(content); err != nil {
+			return nil, err
+		}
+	}
+	return ds, nil
+}
+
+// VerifyDS calculates the DS records reported by PowerDNS from the DNSKEY of the Cryptokey in zone and returns ErrDSMismatch if they differ.
+func (c *Cryptokey) VerifyDS(zone string) error {
+	dnskey, err := c.ParseDNSKEY()
+	if err != nil {
+		return err
+	}
+	reported, err := c.ParseDS()
+	if err != nil {
+		return err
+	}
+
+	for _, ds := range reported {
+		calculated, err := dnskey.DS(zone, ds.DigestType)
+		if err != nil {
+			return err
+		}
+		if !ds.Equal(calculated) {
+			return fmt.Errorf("%w: reported %s, calculated %s", ErrDSMismatch, ds, calculated)
+		}
+	}
+	return nil
+}
+
+// DSRRset calculates the DS RRset of zone for its parent zone from the key signing keys among cryptokeys.
+// The records use the given digest types, SHA-256 by default. The RRset replaces an existing one if it is passed to RecordsService.Patch.
+func DSRRset(zone string, ttl uint32, cryptokeys []Cryptokey, digestTypes ...uint8) (*RRset, error) {
+	if len(digestTypes) == 0 {
+		digestTypes = []uint8{DigestTypeSHA256}
+	}
+
+	name, _ := SplitZoneVariant(zone)
+	rrSet := &RRset{
+		Name:       String(name),
+		Type:       RRTypePtr(RRTypeDS),
+		TTL:        &ttl,
+		ChangeType: ChangeTypePtr(ChangeTypeReplace),
+		Records:    make([]Record, 0, len(cryptokeys)*len(digestTypes)),
+	}
+	for _, cryptokey := range cryptokeys {
+		dnskey, err := cryptokey.ParseDNSKEY()
+		if err != nil {
+			return nil, err
+		}
+		if dnskey.Flags&DNSKEYFlagSEP == 0 {
+			continue
+		}
+
+		for _, digestType := range digestTypes {
+			ds, err := dnskey.DS(zone, digestType)
+			if err != nil {
+				return nil, err
+			}
+			rrSet.Records = append(rrSet.Records, Record{Content: String(ds.String()), Disabled: Bool(false)})
+		}
+	}
+	return rrSet, nil
+}
+
+// PublishDS replaces the DS RRset of zone in its parent zone, which is served by the same PowerDNS server.
+// The DS records are calculated from the key signing keys among cryptokeys or, if cryptokeys is nil, from the active key signing keys of zone.
+func (r *RecordsService) PublishDS(ctx context.Context, parent, zone string, ttl uint32, cryptokeys []Cryptokey) error {
+	ctx = withOperation(ctx, Operation{Service: "Records", Method: "PublishDS", Zone: makeDomainCanonical(parent), RRsetName: makeDomainCanonical(zone), RRsetType: RRTypeDS})
+
+	if cryptokeys == nil {
+		keys, err := r.client.Cryptokeys.List(ctx, zone)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if BoolValue(key.Active) {
+				cryptokeys = append(cryptokeys, key)
+			}
+		}
+	}
+
+	rrSet, err := DSRRset(zone, ttl, cryptokeys)
+	if err != nil {
+		return err
+	}
+	if len(rrSet.Records) == 0 {
+		return fmt.Errorf("zone %s has no key signing key", makeDomainCanonical(zone))
+	}
+	return r.Patch(ctx, parent, &RRsets{Sets: []RRset{*rrSet}})
+}
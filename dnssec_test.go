@@ -0,0 +1,299 @@
+package powerdns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// Examples from RFC 4034, section 5.4, RFC 4509, section 2.3, and RFC 6605, section 6.2
+const (
+	testRSASHA1DNSKEY = "256 3 5 AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw=="
+	testRSASHA1DS     = "60485 5 1 2bb183af5f22588179a53b0a98631fad1a292118"
+	testRSASHA256DS   = "60485 5 2 d4b7d520e7bb5f0f67674a0cceb1e3e0614b93c4f9e99b8383f6a1e4469da50a"
+	testECDSA384Key   = "257 3 14 xKYaNhWdGOfJ+nPrL8/arkwf2EY3MDJ+SErKivBVSum1w/egsXvSADtNJhyem5RCOpgQ6K8X1DRSEkrbYQ+OB+v8/uX45NBwY8rp65F6Glur8I/mlVNgF6W/qTI37m40"
+	testECDSA384DS    = "10771 14 4 72d7b62976ce06438e9c0bf319013cf801f09ecc84b8d7e9495f27e305c6a9b0563a9b5f4d288405c3008a946df983d6"
+	testECDSA256ZSKey = "256 3 13 oJMRESz5E4gYzS/q6XDrvU1qMPYIjCWzJaOau8XNEZeqCYKD5ar0IRd8KqXXFJkqmVfRvMGPmM1x8fGAa2XhSA=="
+)
+
+func TestDNSKEYData(t *testing.T) {
+	dnskey, err := ParseDNSKEY(testRSASHA1DNSKEY)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dnskey.Flags != DNSKEYFlagZone || dnskey.Protocol != 3 || dnskey.Algorithm != 5 || len(dnskey.PublicKey) != 130 || dnskey.RRType() != RRTypeDNSKEY {
+		t.Errorf("unexpected DNSKEY %+v", dnskey)
+	}
+	if dnskey.String() != testRSASHA1DNSKEY {
+		t.Errorf("unexpected content %q", dnskey.String())
+	}
+	if dnskey.KeyTag() != 60485 {
+		t.Errorf("unexpected key tag %d", dnskey.KeyTag())
+	}
+
+	split, err := ParseDNSKEY("256 3 5 ( AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw== )")
+	if err != nil || !reflect.DeepEqual(split, dnskey) {
+		t.Errorf("unexpected DNSKEY %+v: %v", split, err)
+	}
+}
+
+func TestKeyTagRSAMD5(t *testing.T) {
+	dnskey := DNSKEYData{Flags: 257, Protocol: 3, Algorithm: 1, PublicKey: []byte{0x01, 0x03, 0xab, 0xcd, 0xef}}
+	if dnskey.KeyTag() != 0xabcd {
+		t.Errorf("unexpected key tag %x", dnskey.KeyTag())
+	}
+}
+
+func TestDNSKEYDataDS(t *testing.T) {
+	testCases := []struct {
+		zone, dnskey string
+		digestType   uint8
+		want         string
+	}{
+		{"dskey.example.com.", testRSASHA1DNSKEY, DigestTypeSHA1, testRSASHA1DS},
+		{"DSKEY.example.com", testRSASHA1DNSKEY, DigestTypeSHA256, testRSASHA256DS},
+		{"example.net.", testECDSA384Key, DigestTypeSHA384, testECDSA384DS},
+	}
+
+	for _, tc := range testCases {
+		dnskey, _ := ParseDNSKEY(tc.dnskey)
+		ds, err := dnskey.DS(tc.zone, tc.digestType)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ds.String() != tc.want || ds.RRType() != RRTypeDS {
+			t.Errorf("unexpected DS %s, want %s", ds, tc.want)
+		}
+
+		parsed, err := ParseDS(strings.ToUpper(tc.want))
+		if err != nil || !parsed.Equal(ds) {
+			t.Errorf("unexpected DS %+v: %v", parsed, err)
+		}
+	}
+
+	dnskey, _ := ParseDNSKEY(testRSASHA1DNSKEY)
+	if _, err := dnskey.DS("example.com.", 3); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestCanonicalWireName(t *testing.T) {
+	if wire := canonicalWireName("."); string(wire) != "\x00" {
+		t.Errorf("unexpected wire format %q", wire)
+	}
+	if wire := canonicalWireName("Example.COM"); string(wire) != "\x07example\x03com\x00" {
+		t.Errorf("unexpected wire format %q", wire)
+	}
+}
+
+func TestParseDNSSECRecordDataErrors(t *testing.T) {
+	testCases := []struct {
+		content string
+		parse   func(string) error
+	}{
+		{"256 3 5", func(s string) error { _, err := ParseDNSKEY(s); return err }},
+		{"65536 3 5 AAAA", func(s string) error { _, err := ParseDNSKEY(s); return err }},
+		{"256 256 5 AAAA", func(s string) error { _, err := ParseDNSKEY(s); return err }},
+		{"256 3 256 AAAA", func(s string) error { _, err := ParseDNSKEY(s); return err }},
+		{"256 3 5 !!!!", func(s string) error { _, err := ParseDNSKEY(s); return err }},
+		{"60485 5 1", func(s string) error { _, err := ParseDS(s); return err }},
+		{"65536 5 1 00", func(s string) error { _, err := ParseDS(s); return err }},
+		{"60485 256 1 00", func(s string) error { _, err := ParseDS(s); return err }},
+		{"60485 5 256 00", func(s string) error { _, err := ParseDS(s); return err }},
+		{"60485 5 1 zz", func(s string) error { _, err := ParseDS(s); return err }},
+	}
+
+	for _, tc := range testCases {
+		if err := tc.parse(tc.content); err == nil {
+			t.Errorf("%q: error is nil", tc.content)
+		}
+	}
+}
+
+func TestCryptokeyVerifyDS(t *testing.T) {
+	cryptokey := Cryptokey{DNSkey: String(testRSASHA1DNSKEY), DS: []string{testRSASHA1DS, testRSASHA256DS}}
+	if err := cryptokey.VerifyDS("dskey.example.com"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	ds, err := cryptokey.ParseDS()
+	if err != nil || len(ds) != 2 || ds[1].DigestType != DigestTypeSHA256 {
+		t.Errorf("unexpected DS %+v: %v", ds, err)
+	}
+
+	if err := cryptokey.VerifyDS("example.com"); !errors.Is(err, ErrDSMismatch) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	testCases := []Cryptokey{
+		{DNSkey: String("invalid")},
+		{DNSkey: String(testRSASHA1DNSKEY), DS: []string{"invalid"}},
+		{DNSkey: String(testRSASHA1DNSKEY), DS: []string{"60485 5 3 00"}},
+	}
+	for _, cryptokey := range testCases {
+		if err := cryptokey.VerifyDS("dskey.example.com"); err == nil || errors.Is(err, ErrDSMismatch) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestDSRRset(t *testing.T) {
+	cryptokeys := []Cryptokey{{DNSkey: String(testECDSA384Key)}, {DNSkey: String(testECDSA256ZSKey)}}
+
+	rrSet, err := DSRRset("example.net", 3600, cryptokeys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *rrSet.Name != "example.net." || *rrSet.Type != RRTypeDS || *rrSet.TTL != 3600 || *rrSet.ChangeType != ChangeTypeReplace || len(rrSet.Records) != 1 || !strings.HasPrefix(*rrSet.Records[0].Content, "10771 14 2 ") {
+		t.Errorf("unexpected RRset %+v", rrSet)
+	}
+
+	rrSet, err = DSRRset("example.net", 3600, cryptokeys, DigestTypeSHA256, DigestTypeSHA384)
+	if err != nil || len(rrSet.Records) != 2 || *rrSet.Records[1].Content != testECDSA384DS {
+		t.Errorf("unexpected RRset %+v: %v", rrSet, err)
+	}
+
+	variant, err := DSRRset("example.net..internal", 3600, cryptokeys, DigestTypeSHA384)
+	if err != nil || *variant.Name != "example.net." || *variant.Records[0].Content != testECDSA384DS {
+		t.Errorf("unexpected RRset %+v: %v", variant, err)
+	}
+
+	if _, err := DSRRset("example.net", 3600, []Cryptokey{{DNSkey: String("invalid")}}); err == nil {
+		t.Error("error is nil")
+	}
+	if _, err := DSRRset("example.net", 3600, cryptokeys, 3); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func registerPublishDSMockResponder(childDomain, parentDomain string, patches *[]RRsets) {
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/zones/"+makeDomainCanonical(childDomain)+"/cryptokeys",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			cryptokeysMock := []Cryptokey{
+				{ID: Uint64(1), KeyType: String("ksk"), Active: Bool(true), DNSkey: String(testECDSA384Key)},
+				{ID: Uint64(2), KeyType: String("zsk"), Active: Bool(true), DNSkey: String(testECDSA256ZSKey)},
+				{ID: Uint64(3), KeyType: String("ksk"), Active: Bool(false), DNSkey: String(testRSASHA1DNSKEY)},
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, cryptokeysMock)
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodPatch, generateTestAPIVHostURL()+"/zones/"+makeDomainCanonical(parentDomain),
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			var rrSets RRsets
+			if json.NewDecoder(req.Body).Decode(&rrSets) != nil {
+				return httpmock.NewBytesResponse(http.StatusBadRequest, []byte{}), nil
+			}
+			*patches = append(*patches, rrSets)
+			return httpmock.NewBytesResponse(http.StatusNoContent, []byte{}), nil
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/zones/"+makeDomainCanonical(parentDomain),
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			zone := Zone{Name: String(makeDomainCanonical(parentDomain))}
+			if len(*patches) > 0 {
+				zone.RRsets = (*patches)[len(*patches)-1].Sets
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, zone)
+		},
+	)
+}
+
+func TestPublishDS(t *testing.T) {
+	parent := generateNativeZone(true)
+	child := "child." + parent
+	p := initialisePowerDNSTestClient()
+	if httpmock.Disabled() {
+		if _, err := p.Zones.AddNative(context.Background(), child, true, "", false, "", "", true, []string{"ns.foo.tld."}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var patches []RRsets
+	registerPublishDSMockResponder(child, parent, &patches)
+
+	dsContents := func() []string {
+		rrSets, err := p.Records.Get(context.Background(), parent, child, RRTypePtr(RRTypeDS))
+		if err != nil || len(rrSets) != 1 {
+			t.Fatalf("unexpected RRsets %+v: %v", rrSets, err)
+		}
+		contents := make([]string, 0, len(rrSets[0].Records))
+		for _, record := range rrSets[0].Records {
+			contents = append(contents, *record.Content)
+		}
+		slices.Sort(contents)
+		return contents
+	}
+
+	if err := p.Records.PublishDS(context.Background(), parent, child, 86400, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cryptokeys, err := p.Cryptokeys.List(context.Background(), child)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, err := DSRRset(child, 86400, slices.DeleteFunc(cryptokeys, func(cryptokey Cryptokey) bool { return !BoolValue(cryptokey.Active) }))
+	if err != nil || len(want.Records) == 0 {
+		t.Fatalf("unexpected RRset %+v: %v", want, err)
+	}
+	wantContents := make([]string, 0, len(want.Records))
+	for _, record := range want.Records {
+		wantContents = append(wantContents, *record.Content)
+	}
+	slices.Sort(wantContents)
+	if contents := dsContents(); !slices.Equal(contents, wantContents) {
+		t.Errorf("unexpected DS records %v", contents)
+	}
+
+	if err := p.Records.PublishDS(context.Background(), parent, child, 86400, []Cryptokey{{DNSkey: String("257" + strings.TrimPrefix(testRSASHA1DNSKEY, "256"))}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contents := dsContents(); len(contents) != 1 || !strings.HasPrefix(contents[0], "60486 5 2 ") {
+		t.Errorf("unexpected DS records %v", contents)
+	}
+}
+
+func TestPublishDSErrors(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var patches []RRsets
+	registerPublishDSMockResponder("example.net", "net", &patches)
+	p := initialisePowerDNSTestClient()
+
+	if err := p.Records.PublishDS(context.Background(), "net", "example.net", 86400, []Cryptokey{{DNSkey: String(testECDSA256ZSKey)}}); err == nil {
+		t.Error("error is nil")
+	}
+	if err := p.Records.PublishDS(context.Background(), "net", "example.net", 86400, []Cryptokey{{DNSkey: String("invalid")}}); err == nil {
+		t.Error("error is nil")
+	}
+	if err := p.Records.PublishDS(context.Background(), "org", "example.org", 86400, nil); err == nil {
+		t.Error("error is nil")
+	}
+	if len(patches) != 0 {
+		t.Errorf("unexpected patches %+v", patches)
+	}
+}
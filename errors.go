@@ -1,12 +1,123 @@
 package powerdns
 
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrUnauthorized matches errors caused by a missing or invalid API key
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrNotFound matches errors caused by requests for non-existent resources
+	ErrNotFound = errors.New("not found")
+
+	// ErrZoneNotFound matches errors caused by requests for non-existent zones
+	ErrZoneNotFound = errors.New("zone not found")
+
+	// ErrZoneAlreadyExists matches errors caused by creating a zone which already exists
+	ErrZoneAlreadyExists = errors.New("zone already exists")
+
+	// ErrRRsetConflict matches errors caused by duplicate or conflicting RRsets, e.g. a CNAME next to other data
+	ErrRRsetConflict = errors.New("rrset conflict")
+
+	// ErrValidation matches errors caused by requests which have been rejected as invalid
+	ErrValidation = errors.New("validation failed")
+
+	// ErrConcurrentModification is returned if an RRset has been changed by a concurrent writer while it was updated, or if concurrent writers kept undoing the update
+	ErrConcurrentModification = errors.New("concurrent modification")
+
+	// ErrChangeSetConflict is returned if a change cannot be added to a ChangeSet, e.g. because the RRset has already been changed
+	ErrChangeSetConflict = errors.New("change set conflict")
+
+	// ErrRolloverInProgress is returned if a key rollover is started while another one of the same key type has not been completed
+	ErrRolloverInProgress = errors.New("rollover in progress")
+
+	// ErrDSMismatch is returned if the DS records reported by PowerDNS do not match the ones calculated from the DNSKEY
+	ErrDSMismatch = errors.New("DS mismatch")
+
+	// ErrNotCatalogMember is returned if a zone is not a member of the catalog zone it is used with
+	ErrNotCatalogMember = errors.New("not a catalog member")
+
+	// ErrNotProducerZone is returned if a zone is used as catalog, but is not a producer zone
+	ErrNotProducerZone = errors.New("not a producer zone")
+
+	// ErrStatisticType is returned if a statistic is not of the requested type
+	ErrStatisticType = errors.New("unexpected statistic type")
+
+	// ErrSettingNotFound is returned if a configuration setting does not exist
+	ErrSettingNotFound = errors.New("setting not found")
+)
+
 // Error structure with JSON API metadata
 type Error struct {
 	StatusCode int    `json:"-"`
 	Status     string `json:"-"`
 	Message    string `json:"error"`
+
+	// Errors contains additional error messages, e.g. for each RRset which failed validation
+	Errors []string `json:"errors,omitempty"`
+
+	// Method is the HTTP method of the failed request
+	Method string `json:"-"`
+
+	// URL is the URL of the failed request
+	URL string `json:"-"`
+
+	// Operation is the logical API operation of the failed request, if known
+	Operation *Operation `json:"-"`
+
+	// Err is the underlying error, e.g. if the error response could not be decoded
+	Err error `json:"-"`
+
+	// innermostOperation is the operation which actually sent the failed request, e.g. Records.Change within Zones.Import
+	innermostOperation *Operation
 }
 
 func (e Error) Error() string {
 	return e.Message
 }
+
+// Unwrap returns the underlying error, if any.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches one of the sentinel errors of this package.
+func (e Error) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrZoneNotFound:
+		return e.messageContains("could not find domain") || (e.StatusCode == http.StatusNotFound && e.isZonesOperation())
+	case ErrZoneAlreadyExists:
+		return e.StatusCode == http.StatusConflict && e.messageContains("already exists") && e.isZonesOperation()
+	case ErrRRsetConflict:
+		return e.StatusCode == http.StatusUnprocessableEntity && (e.messageContains("conflicts with") || e.messageContains("duplicate rrset"))
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	default:
+		return false
+	}
+}
+
+func (e Error) isZonesOperation() bool {
+	return e.innermostOperation != nil && e.innermostOperation.Service == "Zones"
+}
+
+func (e Error) messageContains(substr string) bool {
+	if strings.Contains(strings.ToLower(e.Message), substr) {
+		return true
+	}
+
+	for _, message := range e.Errors {
+		if strings.Contains(strings.ToLower(message), substr) {
+			return true
+		}
+	}
+
+	return false
+}
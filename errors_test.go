@@ -1,6 +1,11 @@
 package powerdns
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
 
 func TestError(t *testing.T) {
 	myError := &Error{Message: "foo"}
@@ -8,3 +13,57 @@ func TestError(t *testing.T) {
 		t.Error("Error method returns invalid format")
 	}
 }
+
+func TestErrorUnwrap(t *testing.T) {
+	cause := errors.New("cause")
+	var err error = &Error{Message: "foo", Err: cause}
+	if !errors.Is(err, cause) {
+		t.Error("Error does not unwrap the underlying error")
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	zonesOperation := &Operation{Service: "Zones", Method: "Get"}
+	recordsOperation := &Operation{Service: "Records", Method: "Get"}
+
+	testCases := []struct {
+		err        *Error
+		wantTarget error
+		wantNot    []error
+	}{
+		{&Error{StatusCode: http.StatusUnauthorized, Message: "Unauthorized"}, ErrUnauthorized, []error{ErrNotFound, ErrValidation}},
+		{&Error{StatusCode: http.StatusNotFound, Message: "Not Found"}, ErrNotFound, []error{ErrZoneNotFound}},
+		{&Error{StatusCode: http.StatusNotFound, Message: "Could not find domain 'example.com.'"}, ErrZoneNotFound, []error{ErrUnauthorized}},
+		{&Error{StatusCode: http.StatusNotFound, Message: "Not Found", innermostOperation: zonesOperation}, ErrZoneNotFound, []error{ErrZoneAlreadyExists}},
+		{&Error{StatusCode: http.StatusNotFound, Message: "Not Found", innermostOperation: recordsOperation}, ErrNotFound, []error{ErrZoneNotFound}},
+		{&Error{StatusCode: http.StatusNotFound, Message: "Not Found", Operation: zonesOperation, innermostOperation: recordsOperation}, ErrNotFound, []error{ErrZoneNotFound}},
+		{&Error{StatusCode: http.StatusConflict, Message: "Domain 'example.com.' already exists", innermostOperation: zonesOperation}, ErrZoneAlreadyExists, []error{ErrValidation}},
+		{&Error{StatusCode: http.StatusConflict, Message: "A TSIG key with the name 'example' already exists", innermostOperation: &Operation{Service: "TSIGKeys", Method: "Create"}}, nil, []error{ErrZoneAlreadyExists}},
+		{&Error{StatusCode: http.StatusConflict, Message: "Conflict", innermostOperation: zonesOperation}, nil, []error{ErrZoneAlreadyExists}},
+		{&Error{StatusCode: http.StatusUnprocessableEntity, Message: "RRset www.example.com. IN CNAME: Conflicts with pre-existing RRset"}, ErrRRsetConflict, []error{ErrNotFound}},
+		{&Error{StatusCode: http.StatusUnprocessableEntity, Message: "Duplicate RRset www.example.com. IN A with changetype: REPLACE"}, ErrRRsetConflict, nil},
+		{&Error{StatusCode: http.StatusUnprocessableEntity, Message: "Invalid RRsets", Errors: []string{"RRset www.example.com. IN CNAME: Conflicts with another RRset"}}, ErrRRsetConflict, nil},
+		{&Error{StatusCode: http.StatusUnprocessableEntity, Message: "Record www.example.com./A '1.2.3': Parsing record content"}, ErrValidation, []error{ErrRRsetConflict}},
+		{&Error{StatusCode: http.StatusBadRequest, Message: "Bad Request"}, ErrValidation, nil},
+		{&Error{StatusCode: http.StatusInternalServerError, Message: "Internal Server Error"}, nil, []error{ErrUnauthorized, ErrNotFound, ErrZoneNotFound, ErrZoneAlreadyExists, ErrRRsetConflict, ErrValidation, errors.New("foo")}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TestCase%d", i), func(t *testing.T) {
+			var err error = fmt.Errorf("wrapped: %w", tc.err)
+			if tc.wantTarget != nil && !errors.Is(err, tc.wantTarget) {
+				t.Errorf("%q does not match %q", tc.err.Message, tc.wantTarget)
+			}
+			for _, target := range tc.wantNot {
+				if errors.Is(err, target) {
+					t.Errorf("%q unexpectedly matches %q", tc.err.Message, target)
+				}
+			}
+
+			var apiError *Error
+			if !errors.As(err, &apiError) || apiError != tc.err {
+				t.Error("errors.As does not return the API error")
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package powerdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+)
+
+// EndpointStatus is the health state of an API endpoint
+type EndpointStatus struct {
+	// URL is the base URL of the endpoint
+	URL string
+	// Healthy is false if the last request to the endpoint failed with a connection error or a 5xx response
+	Healthy bool
+}
+
+// failover spreads requests across the base URL of a Client and additional endpoints
+type failover struct {
+	endpoints []string
+
+	mu        sync.Mutex
+	unhealthy map[string]bool
+	next      int
+	active    string
+}
+
+// WithFailover is an option for New to use several API endpoints of PowerDNS servers which share a database.
+// The base URL is the preferred endpoint, which receives all other requests than GET and HEAD while it is healthy.
+// GET and HEAD requests are distributed round-robin across all healthy endpoints.
+//
+// A request which fails with a connection error or a 5xx response (except "501 Not Implemented") is sent to the next endpoint,
+// and the failed endpoint is skipped until it has been found healthy again, either by CheckEndpoints or because all other endpoints failed too.
+// Requests with other methods than GET, HEAD, PUT and DELETE, which are not idempotent, are only sent to the next endpoint
+// if the connection to the failed endpoint could not be established, since they may have been applied anyway.
+// Failover happens for each attempt if a RetryPolicy is configured, and before the middlewares, which see the request to the actual endpoint.
+func WithFailover(endpoints ...string) NewOption {
+	return func(client *Client) {
+		client.failover = &failover{endpoints: endpoints, unhealthy: make(map[string]bool)}
+	}
+}
+
+type endpointContextKey struct{}
+
+// candidates returns the endpoints in the order in which they are tried for req, healthy ones first,
+// and whether req is pinned to a single endpoint by CheckEndpoints
+func (f *failover) candidates(baseURL string, req *http.Request) ([]string, bool) {
+	if endpoint, ok := req.Context().Value(endpointContextKey{}).(string); ok {
+		return []string{endpoint}, true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	candidates := append([]string{baseURL}, f.endpoints...)
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		start := f.next % len(candidates)
+		f.next++
+		candidates = append(candidates[start:], candidates[:start]...)
+	}
+
+	slices.SortStableFunc(candidates, func(a, b string) int {
+		switch {
+		case f.unhealthy[a] == f.unhealthy[b]:
+			return 0
+		case f.unhealthy[a]:
+			return 1
+		default:
+			return -1
+		}
+	})
+
+	if !isRewindable(req) {
+		return candidates[:1], false
+	}
+	return candidates, false
+}
+
+// report updates the health state of endpoint and makes it the active endpoint if it has answered a request which is not pinned
+func (f *failover) report(endpoint string, healthy, pinned bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.unhealthy[endpoint] = !healthy
+	if healthy && !pinned {
+		f.active = endpoint
+	}
+}
+
+func isFailover(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+// isResendable reports whether a request with method, which failed with err, can be sent to another endpoint
+func isResendable(method string, err error) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+}
+
+// endpointRequest returns a copy of req which is sent to endpoint, with a fresh body unless it is the first attempt
+func endpointRequest(req *http.Request, endpoint string, first bool) (*http.Request, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if !first {
+		if req, err = rewindRequest(req); err != nil {
+			return nil, err
+		}
+	}
+
+	endpointReq := req.Clone(req.Context())
+	endpointReq.URL.Scheme = endpointURL.Scheme
+	endpointReq.URL.Host = endpointURL.Host
+	endpointReq.URL.User = endpointURL.User
+	endpointReq.Host = ""
+	return endpointReq, nil
+}
+
+func (f *failover) doer(baseURL string, next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		candidates, pinned := f.candidates(baseURL, req)
+		for i := 0; ; i++ {
+			endpointReq, err := endpointRequest(req, candidates[i], i == 0)
+			if err != nil {
+				return nil, err
+			}
+
+			resp, err := next.Do(endpointReq)
+			failed := isFailover(req.Context(), resp, err)
+			f.report(candidates[i], !failed, pinned)
+			if !failed || i == len(candidates)-1 || !isResendable(req.Method, err) {
+				return resp, err
+			}
+			discardResponse(resp)
+		}
+	})
+}
+
+// ActiveEndpoint returns the base URL of the endpoint which answered the latest request, or the base URL of the Client if there has been none yet
+func (p *Client) ActiveEndpoint() string {
+	if p.failover == nil {
+		return p.BaseURL
+	}
+
+	p.failover.mu.Lock()
+	defer p.failover.mu.Unlock()
+
+	if p.failover.active == "" {
+		return p.BaseURL
+	}
+	return p.failover.active
+}
+
+// Endpoints returns the health state of the base URL and the endpoints configured by WithFailover
+func (p *Client) Endpoints() []EndpointStatus {
+	if p.failover == nil {
+		return []EndpointStatus{{URL: p.BaseURL, Healthy: true}}
+	}
+
+	p.failover.mu.Lock()
+	defer p.failover.mu.Unlock()
+
+	statuses := make([]EndpointStatus, 0, len(p.failover.endpoints)+1)
+	for _, endpoint := range append([]string{p.BaseURL}, p.failover.endpoints...) {
+		statuses = append(statuses, EndpointStatus{URL: endpoint, Healthy: !p.failover.unhealthy[endpoint]})
+	}
+	return statuses
+}
+
+// CheckEndpoints retrieves the server from every endpoint and updates their health state accordingly.
+// It returns the errors of all endpoints which could not be retrieved.
+func (p *Client) CheckEndpoints(ctx context.Context) error {
+	var errs []error
+	for _, endpoint := range p.Endpoints() {
+		if _, err := p.Servers.Get(context.WithValue(ctx, endpointContextKey{}, endpoint.URL), p.VHost); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint.URL, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunHealthChecks calls CheckEndpoints immediately and on every interval until ctx is done.
+// It returns the error of ctx.
+func (p *Client) RunHealthChecks(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = p.CheckEndpoints(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package powerdns
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+const (
+	testNode2URL = "http://node2:8080"
+	testNode3URL = "http://node3:8080"
+)
+
+type failoverMockRequest struct {
+	method, host, body string
+}
+
+// failoverMockRequests records requests, which httpmock may receive from another goroutine if the request context can be canceled
+type failoverMockRequests struct {
+	mu       sync.Mutex
+	requests []failoverMockRequest
+}
+
+func (r *failoverMockRequests) add(request failoverMockRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, request)
+}
+
+func (r *failoverMockRequests) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = nil
+}
+
+func (r *failoverMockRequests) hosts() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hosts := make([]string, len(r.requests))
+	for i, request := range r.requests {
+		hosts[i] = request.host
+	}
+	return strings.Join(hosts, " ")
+}
+
+func (r *failoverMockRequests) bodies() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bodies := make([]string, len(r.requests))
+	for i, request := range r.requests {
+		bodies[i] = request.body
+	}
+	return bodies
+}
+
+// registerFailoverMockResponder responds to requests for all hosts with the status of the host,
+// or a connection error if it is -1, or an error after the request has been sent if it is -2
+func registerFailoverMockResponder(statuses map[string]int) *failoverMockRequests {
+	requests := &failoverMockRequests{}
+	responder := func(req *http.Request) (*http.Response, error) {
+		if res := verifyAPIKey(req); res != nil {
+			return res, nil
+		}
+
+		body := ""
+		if req.Body != nil {
+			bodyBytes, _ := io.ReadAll(req.Body)
+			body = string(bodyBytes)
+		}
+		requests.add(failoverMockRequest{method: req.Method, host: req.URL.Host, body: body})
+
+		switch status := statuses[req.URL.Host]; status {
+		case -1:
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		case -2:
+			return nil, io.ErrUnexpectedEOF
+		case 0:
+			return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+		default:
+			return httpmock.NewStringResponse(status, http.StatusText(status)), nil
+		}
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodPost} {
+		httpmock.RegisterResponder(method, `=~^http://[^/]+/api/v1/servers/localhost`, responder)
+	}
+	return requests
+}
+
+func initialiseFailoverTestClient(options ...NewOption) *Client {
+	return New(testBaseURL, testVHost, append([]NewOption{WithAPIKey(testAPIKey), WithFailover(testNode2URL, testNode3URL)}, options...)...)
+}
+
+func TestFailoverRouting(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("failover requires mocked endpoints")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	requests := registerFailoverMockResponder(map[string]int{})
+
+	p := initialiseFailoverTestClient()
+	if p.ActiveEndpoint() != testBaseURL {
+		t.Errorf("unexpected active endpoint %s", p.ActiveEndpoint())
+	}
+
+	for range 4 {
+		if _, err := p.Servers.Get(context.Background(), testVHost); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if p.ActiveEndpoint() != testBaseURL {
+		t.Errorf("unexpected active endpoint %s", p.ActiveEndpoint())
+	}
+
+	for range 2 {
+		req, _ := p.newRequest(context.Background(), http.MethodPut, "servers/localhost/failover", nil, "foo")
+		if _, err := p.do(req, nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if requests.hosts() != "localhost:8080 node2:8080 node3:8080 localhost:8080 localhost:8080 localhost:8080" {
+		t.Errorf("unexpected requests %s", requests.hosts())
+	}
+}
+
+func TestFailover(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("failover requires mocked endpoints")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	statuses := map[string]int{"localhost:8080": http.StatusServiceUnavailable, "node2:8080": -1}
+	requests := registerFailoverMockResponder(statuses)
+
+	var middlewareHosts []string
+	p := initialiseFailoverTestClient(WithMiddleware(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			middlewareHosts = append(middlewareHosts, req.URL.Host)
+			return next.Do(req)
+		})
+	}))
+
+	req, _ := p.newRequest(context.Background(), http.MethodPut, "servers/localhost/failover", nil, "foo")
+	if _, err := p.do(req, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if requests.hosts() != "localhost:8080 node2:8080 node3:8080" || !slices.Equal(middlewareHosts, []string{"localhost:8080", "node2:8080", "node3:8080"}) {
+		t.Errorf("unexpected requests %s %v", requests.hosts(), middlewareHosts)
+	}
+	for _, body := range requests.bodies() {
+		if body != "\"foo\"\n" {
+			t.Errorf("unexpected body %q", body)
+		}
+	}
+	if p.ActiveEndpoint() != testNode3URL {
+		t.Errorf("unexpected active endpoint %s", p.ActiveEndpoint())
+	}
+	if endpoints := p.Endpoints(); !slices.Equal(endpoints, []EndpointStatus{{testBaseURL, false}, {testNode2URL, false}, {testNode3URL, true}}) {
+		t.Errorf("unexpected endpoints %+v", endpoints)
+	}
+
+	// Healthy endpoints are preferred
+	requests.reset()
+	for range 2 {
+		if _, err := p.Servers.Get(context.Background(), testVHost); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if requests.hosts() != "node3:8080 node3:8080" {
+		t.Errorf("unexpected requests %s", requests.hosts())
+	}
+
+	// Unhealthy endpoints are tried as last resort, and the error of the last endpoint is returned if all endpoints fail
+	requests.reset()
+	statuses["node3:8080"] = http.StatusBadGateway
+	if _, err := p.Servers.Get(context.Background(), testVHost); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	delete(statuses, "node2:8080")
+	if _, err := p.Servers.Get(context.Background(), testVHost); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if requests.hosts() != "node3:8080 localhost:8080 node2:8080 localhost:8080 node2:8080" || p.ActiveEndpoint() != testNode2URL {
+		t.Errorf("unexpected requests %s", requests.hosts())
+	}
+}
+
+func TestFailoverExceptions(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("failover requires mocked endpoints")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	statuses := map[string]int{"localhost:8080": http.StatusNotImplemented}
+	requests := registerFailoverMockResponder(statuses)
+
+	put := func(ctx context.Context) *http.Request {
+		req, _ := initialisePowerDNSTestClient().newRequest(ctx, http.MethodPut, "servers/localhost/failover", nil, "foo")
+		return req
+	}
+
+	// 501 is not a failure of the endpoint
+	if _, err := initialiseFailoverTestClient().do(put(context.Background()), nil); err == nil {
+		t.Error("error is nil")
+	}
+
+	// The body of the request cannot be sent again
+	statuses["localhost:8080"] = http.StatusServiceUnavailable
+	req := put(context.Background())
+	req.GetBody = nil
+	if _, err := initialiseFailoverTestClient().do(req, nil); err == nil {
+		t.Error("error is nil")
+	}
+
+	req = put(context.Background())
+	req.GetBody = func() (io.ReadCloser, error) {
+		return nil, errors.New("body is gone")
+	}
+	if _, err := initialiseFailoverTestClient().do(req, nil); err == nil || err.Error() != "body is gone" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if requests.hosts() != "localhost:8080 localhost:8080 localhost:8080" {
+		t.Errorf("unexpected requests %s", requests.hosts())
+	}
+
+	// The request has been canceled
+	requests.reset()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := initialiseFailoverTestClient().do(put(ctx), nil); err == nil {
+		t.Error("error is nil")
+	}
+	if strings.Contains(requests.hosts(), "node") {
+		t.Errorf("unexpected requests %s", requests.hosts())
+	}
+
+	// The endpoint is invalid
+	p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithFailover("://"))
+	if _, err := p.do(put(context.Background()), nil); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestFailoverNonIdempotent(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("failover requires mocked endpoints")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	statuses := map[string]int{"localhost:8080": http.StatusServiceUnavailable}
+	requests := registerFailoverMockResponder(statuses)
+
+	post := func(p *Client) error {
+		req, _ := p.newRequest(context.Background(), http.MethodPost, "servers/localhost/failover", nil, "foo")
+		_, err := p.do(req, nil)
+		return err
+	}
+
+	// A request which may have been applied is not sent again
+	p := initialiseFailoverTestClient()
+	if err := post(p); err == nil {
+		t.Error("error is nil")
+	}
+	statuses["localhost:8080"] = -2
+	if err := post(initialiseFailoverTestClient()); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if requests.hosts() != "localhost:8080 localhost:8080" {
+		t.Errorf("unexpected requests %s", requests.hosts())
+	}
+	if endpoints := p.Endpoints(); endpoints[0].Healthy {
+		t.Errorf("unexpected endpoints %+v", endpoints)
+	}
+
+	// A request which could not be sent fails over
+	requests.reset()
+	statuses["localhost:8080"] = -1
+	if err := post(initialiseFailoverTestClient()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if requests.hosts() != "localhost:8080 node2:8080" {
+		t.Errorf("unexpected requests %s", requests.hosts())
+	}
+}
+
+func TestCheckEndpoints(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("failover requires mocked endpoints")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	statuses := map[string]int{"node2:8080": http.StatusServiceUnavailable}
+	requests := registerFailoverMockResponder(statuses)
+
+	p := initialiseFailoverTestClient(WithRetryPolicy(RetryPolicy{}))
+	err := p.CheckEndpoints(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), testNode2URL+": ") || strings.Contains(err.Error(), testNode3URL) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if requests.hosts() != "localhost:8080 node2:8080 node3:8080" || p.ActiveEndpoint() != testBaseURL {
+		t.Errorf("unexpected requests %s", requests.hosts())
+	}
+	if endpoints := p.Endpoints(); !slices.Equal(endpoints, []EndpointStatus{{testBaseURL, true}, {testNode2URL, false}, {testNode3URL, true}}) {
+		t.Errorf("unexpected endpoints %+v", endpoints)
+	}
+
+	delete(statuses, "node2:8080")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.RunHealthChecks(ctx, time.Hour); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if endpoints := p.Endpoints(); !slices.Equal(endpoints, []EndpointStatus{{testBaseURL, true}, {testNode2URL, true}, {testNode3URL, true}}) {
+		t.Errorf("unexpected endpoints %+v", endpoints)
+	}
+}
+
+func TestEndpointsWithoutFailover(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerFailoverMockResponder(map[string]int{})
+
+	p := initialisePowerDNSTestClient()
+	if p.ActiveEndpoint() != testBaseURL || !slices.Equal(p.Endpoints(), []EndpointStatus{{testBaseURL, true}}) {
+		t.Errorf("unexpected endpoints %+v", p.Endpoints())
+	}
+	if err := p.CheckEndpoints(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/joeig/go-powerdns/v3
 
-go 1.22.7
+go 1.23
 
 require github.com/jarcoal/httpmock v1.4.1
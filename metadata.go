@@ -84,7 +84,9 @@ type Metadata struct {
 
 // List retrieves all metadata for a zone
 func (m *MetadataService) List(ctx context.Context, domain string) ([]Metadata, error) {
-	req, err := m.client.newRequest(ctx, http.MethodGet, path.Join("servers", m.client.VHost, "zones", makeDomainCanonical(domain), "metadata"), nil, nil)
+	ctx = withOperation(ctx, Operation{Service: "Metadata", Method: "List", Zone: makeDomainCanonical(domain)})
+
+	req, err := m.client.newRequest(ctx, http.MethodGet, path.Join("servers", m.client.VHost, "zones", zoneID(domain), "metadata"), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -96,12 +98,14 @@ func (m *MetadataService) List(ctx context.Context, domain string) ([]Metadata,
 
 // Create creates a new metadata entry for a zone
 func (m *MetadataService) Create(ctx context.Context, domain string, kind MetadataKind, values []string) (*Metadata, error) {
+	ctx = withOperation(ctx, Operation{Service: "Metadata", Method: "Create", Zone: makeDomainCanonical(domain)})
+
 	metadata := Metadata{
 		Kind:     &kind,
 		Metadata: values,
 	}
 
-	req, err := m.client.newRequest(ctx, http.MethodPost, path.Join("servers", m.client.VHost, "zones", makeDomainCanonical(domain), "metadata"), nil, metadata)
+	req, err := m.client.newRequest(ctx, http.MethodPost, path.Join("servers", m.client.VHost, "zones", zoneID(domain), "metadata"), nil, metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +117,9 @@ func (m *MetadataService) Create(ctx context.Context, domain string, kind Metada
 
 // Get retrieves a specific metadata kind for a zone
 func (m *MetadataService) Get(ctx context.Context, domain string, kind MetadataKind) (*Metadata, error) {
-	req, err := m.client.newRequest(ctx, http.MethodGet, path.Join("servers", m.client.VHost, "zones", makeDomainCanonical(domain), "metadata", string(kind)), nil, nil)
+	ctx = withOperation(ctx, Operation{Service: "Metadata", Method: "Get", Zone: makeDomainCanonical(domain)})
+
+	req, err := m.client.newRequest(ctx, http.MethodGet, path.Join("servers", m.client.VHost, "zones", zoneID(domain), "metadata", string(kind)), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -125,12 +131,14 @@ func (m *MetadataService) Get(ctx context.Context, domain string, kind MetadataK
 
 // Set creates or modifies a metadata kind for a zone (existing entries for the zone with the same kind are removed)
 func (m *MetadataService) Set(ctx context.Context, domain string, kind MetadataKind, values []string) (*Metadata, error) {
+	ctx = withOperation(ctx, Operation{Service: "Metadata", Method: "Set", Zone: makeDomainCanonical(domain)})
+
 	metadata := Metadata{
 		Kind:     &kind,
 		Metadata: values,
 	}
 
-	req, err := m.client.newRequest(ctx, http.MethodPut, path.Join("servers", m.client.VHost, "zones", makeDomainCanonical(domain), "metadata", string(kind)), nil, metadata)
+	req, err := m.client.newRequest(ctx, http.MethodPut, path.Join("servers", m.client.VHost, "zones", zoneID(domain), "metadata", string(kind)), nil, metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +150,9 @@ func (m *MetadataService) Set(ctx context.Context, domain string, kind MetadataK
 
 // Delete removes a metadata kind from a zone
 func (m *MetadataService) Delete(ctx context.Context, domain string, kind MetadataKind) error {
-	req, err := m.client.newRequest(ctx, http.MethodDelete, path.Join("servers", m.client.VHost, "zones", makeDomainCanonical(domain), "metadata", string(kind)), nil, nil)
+	ctx = withOperation(ctx, Operation{Service: "Metadata", Method: "Delete", Zone: makeDomainCanonical(domain)})
+
+	req, err := m.client.newRequest(ctx, http.MethodDelete, path.Join("servers", m.client.VHost, "zones", zoneID(domain), "metadata", string(kind)), nil, nil)
 	if err != nil {
 		return err
 	}
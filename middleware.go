@@ -0,0 +1,82 @@
+package powerdns
+
+import (
+	"context"
+	"net/http"
+)
+
+// Doer sends an HTTP request and returns an HTTP response. *http.Client implements Doer.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc is an adapter to allow the use of ordinary functions as Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a Doer in order to inspect or modify requests and responses.
+type Middleware func(next Doer) Doer
+
+// WithMiddleware is an option for New to add middlewares to the request pipeline.
+// The first middleware is the outermost one, i.e. it sees the request first and the response last.
+// Middlewares are invoked for each attempt if a RetryPolicy is configured.
+func WithMiddleware(middlewares ...Middleware) NewOption {
+	return func(client *Client) {
+		client.middlewares = append(client.middlewares, middlewares...)
+	}
+}
+
+// Operation describes the logical API operation a request belongs to.
+type Operation struct {
+	// Service is the name of the service, e.g. "Records".
+	Service string
+
+	// Method is the name of the service method, e.g. "Change".
+	Method string
+
+	// Zone is the canonical name of the zone, if the operation refers to one.
+	Zone string
+
+	// RRsetName is the canonical name of the RRset, if the operation refers to one.
+	RRsetName string
+
+	// RRsetType is the type of the RRset, if the operation refers to one.
+	RRsetType RRType
+}
+
+type operationContextKey struct{}
+
+// innermostOperationContextKey carries the operation of the innermost method, which actually sends the request
+type innermostOperationContextKey struct{}
+
+// OperationFromContext returns the Operation stored in ctx, if any.
+// Middlewares can use it with the request context: OperationFromContext(req.Context())
+func OperationFromContext(ctx context.Context) (*Operation, bool) {
+	operation, ok := ctx.Value(operationContextKey{}).(*Operation)
+	return operation, ok
+}
+
+// withOperation stores operation in ctx unless ctx already carries one, so that the outermost operation wins if methods call each other.
+// The innermost operation is kept separately for matching errors against sentinel errors.
+func withOperation(ctx context.Context, operation Operation) context.Context {
+	ctx = context.WithValue(ctx, innermostOperationContextKey{}, &operation)
+	if _, ok := OperationFromContext(ctx); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, operationContextKey{}, &operation)
+}
+
+func (p *Client) doer() Doer {
+	var doer Doer = p.httpClient
+	for i := len(p.middlewares) - 1; i >= 0; i-- {
+		doer = p.middlewares[i](doer)
+	}
+	if p.failover != nil {
+		doer = p.failover.doer(p.BaseURL, doer)
+	}
+	return doer
+}
@@ -0,0 +1,124 @@
+package powerdns
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestDoerFunc(t *testing.T) {
+	wantResp := &http.Response{StatusCode: http.StatusTeapot}
+	doer := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		return wantResp, nil
+	})
+	if resp, err := doer.Do(nil); resp != wantResp || err != nil {
+		t.Error("DoerFunc does not call the underlying function")
+	}
+}
+
+func TestWithMiddleware(t *testing.T) {
+	p := &Client{}
+	middleware := func(next Doer) Doer {
+		return next
+	}
+	WithMiddleware(middleware)(p)
+	WithMiddleware(middleware, middleware)(p)
+	if len(p.middlewares) != 3 {
+		t.Errorf("Expected 3 middlewares, got %d", len(p.middlewares))
+	}
+}
+
+func TestMiddlewareChain(t *testing.T) {
+	testDomain := generateNativeZone(true)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerRecordMockResponder(testDomain, "")
+
+	invocations := make([]string, 0)
+	operations := make([]Operation, 0)
+	recordingMiddleware := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				invocations = append(invocations, name)
+				if operation, ok := OperationFromContext(req.Context()); ok {
+					operations = append(operations, *operation)
+				}
+				req.Header.Set("X-Signed-By", name)
+				return next.Do(req)
+			})
+		}
+	}
+
+	p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithMiddleware(recordingMiddleware("outer"), recordingMiddleware("inner")))
+	if err := p.Records.Add(context.Background(), testDomain, "www."+testDomain, RRTypeA, 300, []string{"127.0.0.1"}); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if !reflect.DeepEqual(invocations, []string{"outer", "inner"}) {
+		t.Errorf("Unexpected middleware order: %v", invocations)
+	}
+
+	wantOperation := Operation{
+		Service:   "Records",
+		Method:    "Add",
+		Zone:      makeDomainCanonical(testDomain),
+		RRsetName: makeDomainCanonical("www." + testDomain),
+		RRsetType: RRTypeA,
+	}
+	if len(operations) != 2 {
+		t.Errorf("Expected 2 operations, got %d", len(operations))
+	}
+	for _, operation := range operations {
+		if operation != wantOperation {
+			t.Errorf("Unexpected operation: %+v", operation)
+		}
+	}
+}
+
+func TestOperationFromContext(t *testing.T) {
+	if _, ok := OperationFromContext(context.Background()); ok {
+		t.Error("Empty context must not contain an operation")
+	}
+
+	ctx := withOperation(context.Background(), Operation{Service: "Zones", Method: "Get"})
+	ctx = withOperation(ctx, Operation{Service: "Records", Method: "Get"})
+	operation, ok := OperationFromContext(ctx)
+	if !ok || operation.Service != "Zones" || operation.Method != "Get" {
+		t.Errorf("Outermost operation must win, got %+v", operation)
+	}
+}
+
+// recordPayloads is a middleware which records the payloads of requests with method, so that they can be inspected with or without mocks
+// recordRequests is a middleware which records the method and path of each request in requests
+func recordRequests(requests *[]string) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			*requests = append(*requests, req.Method+" "+req.URL.Path)
+			return next.Do(req)
+		})
+	}
+}
+
+func recordPayloads[T any](method string, payloads *[]T) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method == method {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				var payload T
+				if err := json.NewDecoder(body).Decode(&payload); err != nil {
+					return nil, err
+				}
+				*payloads = append(*payloads, payload)
+			}
+			return next.Do(req)
+		})
+	}
+}
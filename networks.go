@@ -0,0 +1,83 @@
+package powerdns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"path"
+	"strconv"
+)
+
+// NetworksService handles communication with the networks related methods of the Client API.
+// Networks select the view which answers queries from their clients and require PowerDNS Authoritative Server 5.0 or later.
+type NetworksService service
+
+// Network structure with JSON API metadata
+type Network struct {
+	Network *netip.Prefix `json:"network,omitempty"`
+	View    *string       `json:"view,omitempty"`
+}
+
+// Networks structure with JSON API metadata
+type Networks struct {
+	Networks []Network `json:"networks"`
+}
+
+// networkPath returns the path of a network, which consists of the masked address and the prefix length
+func (n *NetworksService) networkPath(prefix netip.Prefix) (string, error) {
+	if !prefix.IsValid() {
+		return "", fmt.Errorf("invalid network prefix %s", prefix)
+	}
+	prefix = prefix.Masked()
+	return path.Join("servers", n.client.VHost, "networks", prefix.Addr().String(), strconv.Itoa(prefix.Bits())), nil
+}
+
+// List retrieves a list of Networks and their views
+func (n *NetworksService) List(ctx context.Context) ([]Network, error) {
+	ctx = withOperation(ctx, Operation{Service: "Networks", Method: "List"})
+
+	req, err := n.client.newRequest(ctx, http.MethodGet, path.Join("servers", n.client.VHost, "networks"), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	networks := &Networks{}
+	_, err = n.client.do(req, networks)
+	return networks.Networks, err
+}
+
+// Get returns the Network for a given prefix
+func (n *NetworksService) Get(ctx context.Context, prefix netip.Prefix) (*Network, error) {
+	ctx = withOperation(ctx, Operation{Service: "Networks", Method: "Get"})
+
+	networkPath, err := n.networkPath(prefix)
+	if err != nil {
+		return nil, err
+	}
+	req, err := n.client.newRequest(ctx, http.MethodGet, networkPath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	network := &Network{}
+	_, err = n.client.do(req, network)
+	return network, err
+}
+
+// SetView assigns a view to a given prefix. An empty view removes the network.
+func (n *NetworksService) SetView(ctx context.Context, prefix netip.Prefix, view string) error {
+	ctx = withOperation(ctx, Operation{Service: "Networks", Method: "SetView"})
+
+	networkPath, err := n.networkPath(prefix)
+	if err != nil {
+		return err
+	}
+	req, err := n.client.newRequest(ctx, http.MethodPut, networkPath, nil, Network{View: &view})
+	if err != nil {
+		return err
+	}
+
+	_, err = n.client.do(req, nil)
+	return err
+}
@@ -0,0 +1,165 @@
+package powerdns
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/netip"
+	"slices"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func registerNetworksMockResponder(networks map[netip.Prefix]string) {
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/networks",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			networksMock := Networks{Networks: make([]Network, 0, len(networks))}
+			for prefix, view := range networks {
+				networksMock.Networks = append(networksMock.Networks, Network{Network: &prefix, View: String(view)})
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, networksMock)
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodGet, `=~^`+generateTestAPIVHostURL()+`/networks/([^/]+)/(\d+)\z`,
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			prefix := netip.MustParsePrefix(httpmock.MustGetSubmatch(req, 1) + "/" + httpmock.MustGetSubmatch(req, 2))
+			return httpmock.NewJsonResponse(http.StatusOK, Network{Network: &prefix, View: String(networks[prefix])})
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodPut, `=~^`+generateTestAPIVHostURL()+`/networks/([^/]+)/(\d+)\z`,
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			var network Network
+			if json.NewDecoder(req.Body).Decode(&network) != nil || network.View == nil {
+				return httpmock.NewBytesResponse(http.StatusBadRequest, []byte{}), nil
+			}
+			prefix := netip.MustParsePrefix(httpmock.MustGetSubmatch(req, 1) + "/" + httpmock.MustGetSubmatch(req, 2))
+			if *network.View == "" {
+				delete(networks, prefix)
+			} else {
+				networks[prefix] = *network.View
+			}
+			return httpmock.NewBytesResponse(http.StatusNoContent, []byte{}), nil
+		},
+	)
+}
+
+func findNetwork(networks []Network, prefix netip.Prefix) int {
+	return slices.IndexFunc(networks, func(network Network) bool {
+		return *network.Network == prefix
+	})
+}
+
+func TestListNetworks(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerNetworksMockResponder(map[netip.Prefix]string{})
+
+	p := initialisePowerDNSTestClient()
+	prefix := netip.MustParsePrefix("192.0.2.0/24")
+	if err := p.Networks.SetView(context.Background(), prefix, "internal"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() { _ = p.Networks.SetView(context.Background(), prefix, "") }()
+
+	networks, err := p.Networks.List(context.Background())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if i := findNetwork(networks, prefix); i < 0 || *networks[i].View != "internal" {
+		t.Errorf("unexpected networks %+v", networks)
+	}
+}
+
+func TestListNetworksError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if _, err := p.Networks.List(context.Background()); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestGetNetwork(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerNetworksMockResponder(map[netip.Prefix]string{})
+
+	p := initialisePowerDNSTestClient()
+	if err := p.Networks.SetView(context.Background(), netip.MustParsePrefix("2001:db8::/32"), "internal"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() { _ = p.Networks.SetView(context.Background(), netip.MustParsePrefix("2001:db8::/32"), "") }()
+
+	network, err := p.Networks.Get(context.Background(), netip.MustParsePrefix("2001:db8::1/32"))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if *network.Network != netip.MustParsePrefix("2001:db8::/32") || *network.View != "internal" {
+		t.Errorf("unexpected network %+v", network)
+	}
+}
+
+func TestGetNetworkError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	if _, err := p.Networks.Get(context.Background(), netip.Prefix{}); err == nil {
+		t.Error("error is nil")
+	}
+
+	p.BaseURL = "://"
+	if _, err := p.Networks.Get(context.Background(), netip.MustParsePrefix("192.0.2.0/24")); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestSetNetworkView(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerNetworksMockResponder(map[netip.Prefix]string{})
+
+	p := initialisePowerDNSTestClient()
+	internal, external := netip.MustParsePrefix("192.0.2.0/24"), netip.MustParsePrefix("198.51.100.0/24")
+	if err := p.Networks.SetView(context.Background(), external, "external"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err := p.Networks.SetView(context.Background(), internal, "internal"); err != nil {
+		t.Errorf("%s", err)
+	}
+	defer func() { _ = p.Networks.SetView(context.Background(), internal, "") }()
+	if err := p.Networks.SetView(context.Background(), external, ""); err != nil {
+		t.Errorf("%s", err)
+	}
+
+	networks, err := p.Networks.List(context.Background())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if i := findNetwork(networks, internal); i < 0 || *networks[i].View != "internal" || findNetwork(networks, external) >= 0 {
+		t.Errorf("unexpected networks %+v", networks)
+	}
+}
+
+func TestSetNetworkViewError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	if err := p.Networks.SetView(context.Background(), netip.Prefix{}, "internal"); err == nil {
+		t.Error("error is nil")
+	}
+
+	p.BaseURL = "://"
+	if err := p.Networks.SetView(context.Background(), netip.MustParsePrefix("192.0.2.0/24"), "internal"); err == nil {
+		t.Error("error is nil")
+	}
+}
@@ -48,19 +48,26 @@ type Client struct {
 	VHost   string
 	Headers map[string]string
 
-	httpClient *http.Client
-	apiKey     *string
+	httpClient  *http.Client
+	apiKey      *string
+	retryPolicy *RetryPolicy
+	middlewares []Middleware
+	failover    *failover
 
 	common service // Reuse a single struct instead of allocating one for each service on the heap
 
-	Config     *ConfigService
-	Cryptokeys *CryptokeysService
-	Metadata   *MetadataService
-	Records    *RecordsService
-	Search     *SearchService
-	Servers    *ServersService
-	Statistics *StatisticsService
-	Zones      *ZonesService
+	Autoprimaries *AutoprimariesService
+	Catalog       *CatalogService
+	Config        *ConfigService
+	Cryptokeys    *CryptokeysService
+	Metadata      *MetadataService
+	Networks      *NetworksService
+	Records       *RecordsService
+	Search        *SearchService
+	Servers       *ServersService
+	Statistics    *StatisticsService
+	Views         *ViewsService
+	Zones         *ZonesService
 	// Deprecated: Use TSIGKeys instead. TSIGKey will be removed with the next major version.
 	TSIGKey  *TSIGKeysService
 	TSIGKeys *TSIGKeysService
@@ -88,13 +95,17 @@ func New(baseURL string, vHost string, options ...NewOption) *Client {
 
 	client.common.client = client
 
+	client.Autoprimaries = (*AutoprimariesService)(&client.common)
+	client.Catalog = (*CatalogService)(&client.common)
 	client.Config = (*ConfigService)(&client.common)
 	client.Cryptokeys = (*CryptokeysService)(&client.common)
 	client.Metadata = (*MetadataService)(&client.common)
+	client.Networks = (*NetworksService)(&client.common)
 	client.Records = (*RecordsService)(&client.common)
 	client.Search = (*SearchService)(&client.common)
 	client.Servers = (*ServersService)(&client.common)
 	client.Statistics = (*StatisticsService)(&client.common)
+	client.Views = (*ViewsService)(&client.common)
 	client.Zones = (*ZonesService)(&client.common)
 	client.TSIGKeys = (*TSIGKeysService)(&client.common)
 	client.TSIGKey = client.TSIGKeys
@@ -136,11 +147,21 @@ func makeDomainCanonical(domain string) string {
 	return fmt.Sprintf("%s.", trimDomain(domain))
 }
 
+// zoneID returns the ID of domain in /zones/{id} paths, which is its canonical name or the ID of a zone variant, e.g. "example.com..internal".
+func zoneID(domain string) string {
+	zone, variant := SplitZoneVariant(domain)
+	return ZoneVariant(zone, variant)
+}
+
 func (p *Client) newRequest(ctx context.Context, method string, pathFragment string, query *url.Values, body interface{}) (*http.Request, error) {
-	var buf io.ReadWriter
+	// A bytes.Reader allows http.NewRequestWithContext to set GetBody, so the body can be sent again on retries.
+	var bodyReader io.Reader
 	if body != nil {
-		buf = new(bytes.Buffer)
-		_ = json.NewEncoder(buf).Encode(body)
+		buf := new(bytes.Buffer)
+		if err := json.NewEncoder(buf).Encode(body); err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(buf.Bytes())
 	}
 
 	apiURL, err := generateAPIURL(p.BaseURL, pathFragment, query)
@@ -148,7 +169,7 @@ func (p *Client) newRequest(ctx context.Context, method string, pathFragment str
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, apiURL.String(), buf)
+	req, err := http.NewRequestWithContext(ctx, method, apiURL.String(), bodyReader)
 	if err != nil {
 		return nil, err
 	}
@@ -172,39 +193,38 @@ func (p *Client) newRequest(ctx context.Context, method string, pathFragment str
 }
 
 func (p *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := p.httpClient.Do(req)
+	resp, err := p.send(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.StatusCode == 401 {
-		return resp, &Error{
-			Status:     resp.Status,
-			StatusCode: resp.StatusCode,
-			Message:    "Unauthorized",
-		}
+		apiError := newResponseError(req, resp)
+		apiError.Message = "Unauthorized"
+		return resp, apiError
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		defer func() {
 			_ = resp.Body.Close()
 		}()
-		var message string
 
-		if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
-			apiError := new(Error)
-			_ = json.NewDecoder(resp.Body).Decode(&apiError)
-			message = apiError.Message
-		} else {
-			messageBytes, _ := io.ReadAll(resp.Body)
-			message = string(messageBytes)
+		apiError := newResponseError(req, resp)
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			apiError.Err = err
 		}
 
-		return resp, &Error{
-			Status:     resp.Status,
-			StatusCode: resp.StatusCode,
-			Message:    message,
+		if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") && err == nil {
+			if err := json.Unmarshal(body, apiError); err != nil {
+				apiError.Message = string(body)
+				apiError.Err = err
+			}
+		} else {
+			apiError.Message = string(body)
 		}
+
+		return resp, apiError
 	}
 
 	if v != nil && resp.StatusCode != http.StatusNoContent {
@@ -217,3 +237,22 @@ func (p *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
 
 	return resp, err
 }
+
+func newResponseError(req *http.Request, resp *http.Response) *Error {
+	apiError := &Error{
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		URL:        req.URL.String(),
+	}
+
+	if operation, ok := OperationFromContext(req.Context()); ok {
+		apiError.Operation = operation
+	}
+
+	if operation, ok := req.Context().Value(innermostOperationContextKey{}).(*Operation); ok {
+		apiError.innermostOperation = operation
+	}
+
+	return apiError
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"net/http"
 	"net/url"
 	"testing"
+	"testing/iotest"
 
 	"github.com/jarcoal/httpmock"
 )
@@ -53,6 +55,35 @@ func registerDoMockResponder() {
 		},
 	)
 
+	httpmock.RegisterResponder("PATCH", fmt.Sprintf("%s/servers/localhost/zones/example.com.", generateTestAPIURL()),
+		func(req *http.Request) (*http.Response, error) {
+			mock := Error{
+				Message: "Invalid RRsets",
+				Errors:  []string{"RRset www.example.com. IN CNAME: Conflicts with pre-existing RRset"},
+			}
+			return httpmock.NewJsonResponse(http.StatusUnprocessableEntity, mock)
+		},
+	)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/servers/localhost/zones/broken.example.com.", generateTestAPIURL()),
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusInternalServerError, "<html>Internal Server Error</html>")
+			resp.Header.Set("Content-Type", "application/json")
+			return resp, nil
+		},
+	)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/servers/localhost/zones/unreadable.example.com.", generateTestAPIURL()),
+		func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Status:     "500 Internal Server Error",
+				StatusCode: http.StatusInternalServerError,
+				Body:       io.NopCloser(iotest.ErrReader(errors.New("connection reset by peer"))),
+				Header:     http.Header{},
+			}, nil
+		},
+	)
+
 	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/server", generateTestAPIURL()),
 		func(req *http.Request) (*http.Response, error) {
 			mock := Error{
@@ -216,6 +247,21 @@ func TestNewRequest(t *testing.T) {
 		}
 	})
 
+	t.Run("TestRewindableBody", func(t *testing.T) {
+		p := initialisePowerDNSTestClient()
+		req, _ := p.newRequest(context.Background(), http.MethodPut, "servers", nil, "test-body")
+		if req.GetBody == nil {
+			t.Error("Request body cannot be rewound")
+		}
+	})
+
+	t.Run("TestInvalidBody", func(t *testing.T) {
+		p := initialisePowerDNSTestClient()
+		if _, err := p.newRequest(context.Background(), http.MethodPut, "servers", nil, make(chan int)); err == nil {
+			t.Error("err expected")
+		}
+	})
+
 	t.Run("TestInvalidMethod", func(t *testing.T) {
 		p := New(testBaseURL, testVHost, WithHeaders(map[string]string{"X-Test-Header": "test-header"}))
 		_, err := p.newRequest(context.Background(), " ", "servers", nil, nil)
@@ -240,9 +286,66 @@ func TestDo(t *testing.T) {
 	t.Run("Test401Handling", func(t *testing.T) {
 		p := New(testBaseURL, testVHost)
 		req, _ := p.newRequest(context.Background(), http.MethodGet, "servers/localhost", nil, nil)
-		if _, err := p.do(req, nil); err.Error() != "Unauthorized" {
+		_, err := p.do(req, nil)
+		if err.Error() != "Unauthorized" {
 			t.Error("401 response does not result into an error with correct message.")
 		}
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Error("401 response does not match ErrUnauthorized.")
+		}
+	})
+	t.Run("TestErrorMetadata", func(t *testing.T) {
+		if httpmock.Disabled() {
+			t.Skip("error responses require mocks")
+		}
+		p := initialisePowerDNSTestClient()
+		ctx := withOperation(context.Background(), Operation{Service: "Records", Method: "Patch", Zone: "example.com."})
+		ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Get", Zone: "example.com."})
+		req, _ := p.newRequest(ctx, http.MethodPatch, "servers/localhost/zones/example.com.", nil, RRsets{})
+		_, err := p.do(req, nil)
+
+		var apiError *Error
+		if !errors.As(err, &apiError) {
+			t.Fatalf("Unexpected error type: %T", err)
+		}
+		if apiError.Method != http.MethodPatch || apiError.URL != generateTestAPIURL()+"/servers/localhost/zones/example.com." {
+			t.Errorf("Unexpected request metadata: %s %s", apiError.Method, apiError.URL)
+		}
+		if apiError.Operation == nil || apiError.Operation.Method != "Patch" {
+			t.Errorf("Unexpected operation: %+v", apiError.Operation)
+		}
+		if apiError.innermostOperation == nil || apiError.innermostOperation.Service != "Zones" {
+			t.Errorf("Unexpected innermost operation: %+v", apiError.innermostOperation)
+		}
+		if len(apiError.Errors) != 1 || !errors.Is(err, ErrRRsetConflict) {
+			t.Errorf("Unexpected errors: %v", apiError.Errors)
+		}
+	})
+	t.Run("TestUndecodableJSONErrorHandling", func(t *testing.T) {
+		if httpmock.Disabled() {
+			t.Skip("error responses require mocks")
+		}
+		p := initialisePowerDNSTestClient()
+		req, _ := p.newRequest(context.Background(), http.MethodGet, "servers/localhost/zones/broken.example.com.", nil, nil)
+		_, err := p.do(req, nil)
+
+		var apiError *Error
+		if !errors.As(err, &apiError) || apiError.Message != "<html>Internal Server Error</html>" || apiError.Err == nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	t.Run("TestUnreadableErrorHandling", func(t *testing.T) {
+		if httpmock.Disabled() {
+			t.Skip("error responses require mocks")
+		}
+		p := initialisePowerDNSTestClient()
+		req, _ := p.newRequest(context.Background(), http.MethodGet, "servers/localhost/zones/unreadable.example.com.", nil, nil)
+		_, err := p.do(req, nil)
+
+		var apiError *Error
+		if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusInternalServerError || apiError.Err == nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
 	})
 	t.Run("TestErrorHandling", func(t *testing.T) {
 		p := initialisePowerDNSTestClient()
@@ -349,3 +452,23 @@ func TestMakeDomainCanonical(t *testing.T) {
 		})
 	}
 }
+
+func TestZoneID(t *testing.T) {
+	testCases := []struct {
+		domain string
+		wantID string
+	}{
+		{"example.com.", "example.com."},
+		{"example.com", "example.com."},
+		{"example.com..internal", "example.com..internal"},
+		{"example.com...internal.", "example.com..internal"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TestCase%d", i), func(t *testing.T) {
+			if zoneID(tc.domain) != tc.wantID {
+				t.Errorf("zoneID returned %q", zoneID(tc.domain))
+			}
+		})
+	}
+}
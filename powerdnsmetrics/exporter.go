@@ -0,0 +1,202 @@
+// Package powerdnsmetrics exposes the statistics of PowerDNS servers in the Prometheus text exposition format.
+// It has no dependencies besides the powerdns package, and the Exporter can be served as scrape target directly.
+package powerdnsmetrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+// DefaultNamespace is the prefix of all metric names, which matches the metrics endpoint of PowerDNS itself
+const DefaultNamespace = "pdns_auth"
+
+// ContentType is the media type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// defaultGauges are the statistics of PowerDNS which can decrease, all other statistics are counters
+var defaultGauges = []string{
+	"backend-latency", "cache-latency", "fd-usage", "key-cache-size", "latency", "meta-cache-size", "open-tcp-connections",
+	"packetcache-size", "qsize-q", "query-cache-size", "real-memory-usage", "receive-latency", "security-status",
+	"send-latency", "signature-cache-size", "special-memory-usage", "uptime", "xfr-queue", "zone-cache-size",
+}
+
+// Target is a PowerDNS server whose statistics are exported
+type Target struct {
+	// Server is the value of the server label of the series of the target
+	Server string
+
+	// Client is used to retrieve the statistics of the server
+	Client *powerdns.Client
+}
+
+// Exporter retrieves the statistics of its targets and writes them in the text exposition format.
+// Statistics become counters, whose names end with _total, or gauges, map statistics become series with a key label,
+// and ring statistics become gauges with an entry label.
+// A target which cannot be scraped is reported by its up metric, while the other targets are exported regardless.
+type Exporter struct {
+	targets           []Target
+	namespace         string
+	gauges            map[string]bool
+	statisticsOptions []powerdns.StatisticsOption
+}
+
+// Option is a functional option for New.
+type Option func(*Exporter)
+
+// WithNamespace is an option for New to replace DefaultNamespace.
+func WithNamespace(namespace string) Option {
+	return func(e *Exporter) {
+		e.namespace = namespace
+	}
+}
+
+// WithGauges is an option for New to export additional statistics as gauges instead of counters.
+func WithGauges(names ...string) Option {
+	return func(e *Exporter) {
+		for _, name := range names {
+			e.gauges[name] = true
+		}
+	}
+}
+
+// WithoutRings is an option for New to skip the ring statistics, which are expensive to compute on busy servers.
+func WithoutRings() Option {
+	return func(e *Exporter) {
+		e.statisticsOptions = append(e.statisticsOptions, powerdns.WithoutRings())
+	}
+}
+
+// New returns an Exporter for the targets.
+func New(targets []Target, options ...Option) *Exporter {
+	e := &Exporter{
+		targets:   targets,
+		namespace: DefaultNamespace,
+		gauges:    make(map[string]bool, len(defaultGauges)),
+	}
+	for _, name := range defaultGauges {
+		e.gauges[name] = true
+	}
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
+// family is a metric and its samples of all targets
+type family struct {
+	metricType string
+	samples    []string
+}
+
+// Write retrieves the statistics of all targets concurrently and writes them to w.
+// Errors of targets are reported by the up metric, so only errors of w are returned.
+func (e *Exporter) Write(ctx context.Context, w io.Writer) error {
+	results := make([]powerdns.TypedStatistics, len(e.targets))
+	errs := make([]error, len(e.targets))
+
+	var wg sync.WaitGroup
+	for i, target := range e.targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = target.Client.Statistics.ListTyped(ctx, e.statisticsOptions...)
+		}()
+	}
+	wg.Wait()
+
+	families := make(map[string]*family)
+	add := func(statistic, metricType, labels string, value uint64) {
+		name := e.metricName(statistic, metricType)
+		f, ok := families[name]
+		if !ok {
+			f = &family{metricType: metricType}
+			families[name] = f
+		}
+		f.samples = append(f.samples, name+"{"+labels+"} "+strconv.FormatUint(value, 10))
+	}
+
+	for i, target := range e.targets {
+		server := label("server", target.Server)
+		if errs[i] != nil {
+			add("up", "gauge", server, 0)
+			continue
+		}
+		add("up", "gauge", server, 1)
+
+		for _, statistic := range results[i] {
+			switch statistic := statistic.(type) {
+			case powerdns.StatisticItem:
+				add(statistic.Name, e.metricType(statistic.Name), server, statistic.Value)
+			case powerdns.MapStatisticItem:
+				for _, entry := range statistic.Entries {
+					add(statistic.Name, e.metricType(statistic.Name), server+","+label("key", entry.Name), entry.Value)
+				}
+			case powerdns.RingStatisticItem:
+				add("ring_"+statistic.Name+"_size", "gauge", server, statistic.Size)
+				for _, entry := range statistic.Entries {
+					add("ring_"+statistic.Name, "gauge", server+","+label("entry", entry.Name), entry.Value)
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, families[name].metricType)
+		for _, sample := range families[name].samples {
+			b.WriteString(sample + "\n")
+		}
+	}
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// ServeHTTP writes the statistics of all targets as response to a scrape.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_ = e.Write(r.Context(), w)
+}
+
+func (e *Exporter) metricType(statistic string) string {
+	if e.gauges[statistic] {
+		return "gauge"
+	}
+	return "counter"
+}
+
+// metricName prefixes the name of a statistic with the namespace, replaces the characters which are invalid in metric names
+// and, as required by the naming conventions, appends the _total suffix to counters
+func (e *Exporter) metricName(statistic, metricType string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == ':' {
+			return r
+		}
+		return '_'
+	}, e.namespace+"_"+statistic)
+	if metricType == "counter" {
+		name += "_total"
+	}
+	return name
+}
+
+// labelValueReplacer escapes label values according to the text exposition format
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func label(name, value string) string {
+	return name + `="` + labelValueReplacer.Replace(value) + `"`
+}
@@ -0,0 +1,117 @@
+package powerdnsmetrics
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joeig/go-powerdns/v3"
+	"github.com/joeig/go-powerdns/v3/powerdnstest"
+)
+
+func newTestTarget(t *testing.T, server string, statistics ...powerdns.Statistic) Target {
+	t.Helper()
+
+	s := powerdnstest.NewServer(powerdnstest.WithStatistics(statistics...))
+	t.Cleanup(s.Close)
+	return Target{Server: server, Client: s.Client()}
+}
+
+func entries(pairs ...string) []any {
+	values := make([]any, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		values = append(values, map[string]any{"name": pairs[i], "value": pairs[i+1]})
+	}
+	return values
+}
+
+func TestExporter(t *testing.T) {
+	primary := newTestTarget(t, "primary",
+		powerdns.Statistic{Name: powerdns.String("udp-queries"), Type: powerdns.String("StatisticItem"), Value: "42"},
+		powerdns.Statistic{Name: powerdns.String("uptime"), Type: powerdns.String("StatisticItem"), Value: "3600"},
+		powerdns.Statistic{Name: powerdns.String("response-by-qtype"), Type: powerdns.String("MapStatisticItem"), Value: entries("A", "40", "AAAA", "2")},
+		powerdns.Statistic{Name: powerdns.String("queries"), Type: powerdns.String("RingStatisticItem"), Size: powerdns.String("10000"), Value: entries(`example.com/"A"`, "7")},
+	)
+	secondary := newTestTarget(t, `secondary\1`,
+		powerdns.Statistic{Name: powerdns.String("udp-queries"), Type: powerdns.String("StatisticItem"), Value: "23"},
+	)
+	unreachable := powerdnstest.NewServer()
+	unreachable.Close()
+
+	exporter := New([]Target{primary, secondary, {Server: "unreachable", Client: unreachable.Client(powerdns.WithRetryPolicy(powerdns.RetryPolicy{}))}})
+
+	var b strings.Builder
+	if err := exporter.Write(context.Background(), &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `# TYPE pdns_auth_response_by_qtype_total counter
+pdns_auth_response_by_qtype_total{server="primary",key="A"} 40
+pdns_auth_response_by_qtype_total{server="primary",key="AAAA"} 2
+# TYPE pdns_auth_ring_queries gauge
+pdns_auth_ring_queries{server="primary",entry="example.com/\"A\""} 7
+# TYPE pdns_auth_ring_queries_size gauge
+pdns_auth_ring_queries_size{server="primary"} 10000
+# TYPE pdns_auth_udp_queries_total counter
+pdns_auth_udp_queries_total{server="primary"} 42
+pdns_auth_udp_queries_total{server="secondary\\1"} 23
+# TYPE pdns_auth_up gauge
+pdns_auth_up{server="primary"} 1
+pdns_auth_up{server="secondary\\1"} 1
+pdns_auth_up{server="unreachable"} 0
+# TYPE pdns_auth_uptime gauge
+pdns_auth_uptime{server="primary"} 3600
+`
+	if b.String() != want {
+		t.Errorf("unexpected exposition:\n%s", b.String())
+	}
+}
+
+func TestExporterOptions(t *testing.T) {
+	target := newTestTarget(t, "primary",
+		powerdns.Statistic{Name: powerdns.String("udp-queries"), Type: powerdns.String("StatisticItem"), Value: "42"},
+		powerdns.Statistic{Name: powerdns.String("queries"), Type: powerdns.String("RingStatisticItem"), Size: powerdns.String("10000"), Value: entries("example.com/A", "7")},
+	)
+
+	exporter := New([]Target{target}, WithNamespace("dns"), WithGauges("udp-queries"), WithoutRings())
+
+	var b strings.Builder
+	if err := exporter.Write(context.Background(), &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `# TYPE dns_udp_queries gauge
+dns_udp_queries{server="primary"} 42
+# TYPE dns_up gauge
+dns_up{server="primary"} 1
+`
+	if b.String() != want {
+		t.Errorf("unexpected exposition:\n%s", b.String())
+	}
+}
+
+func TestExporterServeHTTP(t *testing.T) {
+	target := newTestTarget(t, "primary", powerdns.Statistic{Name: powerdns.String("udp-queries"), Type: powerdns.String("StatisticItem"), Value: "42"})
+
+	recorder := httptest.NewRecorder()
+	New([]Target{target}).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	if recorder.Header().Get("Content-Type") != ContentType || !strings.Contains(recorder.Body.String(), `pdns_auth_udp_queries_total{server="primary"} 42`) {
+		t.Errorf("unexpected response %v %s", recorder.Header(), recorder.Body.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestExporterWriteError(t *testing.T) {
+	target := newTestTarget(t, "primary", powerdns.Statistic{Name: powerdns.String("udp-queries"), Type: powerdns.String("StatisticItem"), Value: "42"})
+	if err := New([]Target{target}).Write(context.Background(), failingWriter{}); err == nil {
+		t.Error("error is nil")
+	}
+}
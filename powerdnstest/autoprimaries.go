@@ -0,0 +1,52 @@
+package powerdnstest
+
+import (
+	"net/http"
+	"net/netip"
+	"slices"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func (s *Server) lookupAutoprimary(ip, nameServer string) int {
+	return slices.IndexFunc(s.autoprimaries, func(autoprimary powerdns.Autoprimary) bool {
+		return *autoprimary.IP == ip && *autoprimary.NameServer == nameServer
+	})
+}
+
+func (s *Server) listAutoprimaries(*http.Request) (int, any, *apiError) {
+	return http.StatusOK, s.autoprimaries, nil
+}
+
+func (s *Server) postAutoprimary(r *http.Request) (int, any, *apiError) {
+	var in powerdns.Autoprimary
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+	if _, err := netip.ParseAddr(powerdns.StringValue(in.IP)); err != nil {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "ip '%s' is invalid", powerdns.StringValue(in.IP))
+	}
+	if powerdns.StringValue(in.NameServer) == "" {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "Nameserver is empty")
+	}
+	if s.lookupAutoprimary(*in.IP, *in.NameServer) >= 0 {
+		return 0, nil, errorf(http.StatusConflict, "Autoprimary %s/%s already exists", *in.IP, *in.NameServer)
+	}
+
+	s.autoprimaries = append(s.autoprimaries, powerdns.Autoprimary{
+		IP:         in.IP,
+		NameServer: in.NameServer,
+		Account:    powerdns.String(powerdns.StringValue(in.Account)),
+	})
+	return http.StatusCreated, nil, nil
+}
+
+func (s *Server) deleteAutoprimary(r *http.Request) (int, any, *apiError) {
+	i := s.lookupAutoprimary(r.PathValue("ip"), r.PathValue("nameserver"))
+	if i < 0 {
+		return 0, nil, errorf(http.StatusNotFound, "Autoprimary %s/%s not found", r.PathValue("ip"), r.PathValue("nameserver"))
+	}
+
+	s.autoprimaries = slices.Delete(s.autoprimaries, i, i+1)
+	return http.StatusNoContent, nil, nil
+}
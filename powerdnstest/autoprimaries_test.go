@@ -0,0 +1,56 @@
+package powerdnstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func TestAutoprimaries(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	if err := client.Autoprimaries.Add(ctx, "2001:db8::1", "ns1.example.com", "example"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := client.Autoprimaries.Add(ctx, "192.0.2.1", "ns2.example.com", ""); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := client.Autoprimaries.Add(ctx, "192.0.2.1", "ns2.example.com", "example"); !isConflict(err) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	autoprimaries, err := client.Autoprimaries.List(ctx)
+	if err != nil || len(autoprimaries) != 2 || *autoprimaries[0].Account != "example" || *autoprimaries[1].Account != "" {
+		t.Errorf("unexpected autoprimaries %+v: %v", autoprimaries, err)
+	}
+
+	if err := client.Autoprimaries.Delete(ctx, "2001:db8::1", "ns1.example.com"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := client.Autoprimaries.Delete(ctx, "2001:db8::1", "ns1.example.com"); !errors.Is(err, powerdns.ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	autoprimaries, err = client.Autoprimaries.List(ctx)
+	if err != nil || len(autoprimaries) != 1 || *autoprimaries[0].IP != "192.0.2.1" {
+		t.Errorf("unexpected autoprimaries %+v: %v", autoprimaries, err)
+	}
+}
+
+func TestAutoprimaryErrors(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	if status, _ := rawRequest(t, s, http.MethodPost, "/autoprimaries", "{"); status != http.StatusBadRequest {
+		t.Errorf("unexpected status %d", status)
+	}
+	if status, _ := rawRequest(t, s, http.MethodPost, "/autoprimaries", `{"ip": "invalid", "nameserver": "ns1.example.com"}`); status != http.StatusUnprocessableEntity {
+		t.Errorf("unexpected status %d", status)
+	}
+	if status, _ := rawRequest(t, s, http.MethodPost, "/autoprimaries", `{"ip": "192.0.2.1"}`); status != http.StatusUnprocessableEntity {
+		t.Errorf("unexpected status %d", status)
+	}
+}
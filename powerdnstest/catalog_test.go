@@ -0,0 +1,65 @@
+package powerdnstest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func TestCatalog(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+	addTestZone(t, s, "example.org")
+	addTestZone(t, s, "example.net")
+
+	catalog, err := client.Catalog.Create(ctx, "catalog.example")
+	if err != nil || *catalog.Kind != powerdns.ProducerZoneKind {
+		t.Fatalf("unexpected catalog %+v: %v", catalog, err)
+	}
+
+	for _, member := range []string{"example.org", "example.com"} {
+		if err := client.Catalog.AddMember(ctx, "catalog.example", member); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if err := client.Catalog.AddMember(ctx, "example.net", "example.org"); !errors.Is(err, powerdns.ErrNotProducerZone) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	members, err := client.Catalog.Members(ctx, "catalog.example")
+	if err != nil || len(members) != 2 || *members[0].Name != "example.com." || *members[1].Name != "example.org." {
+		t.Errorf("unexpected members %+v: %v", members, err)
+	}
+
+	if err := client.Catalog.SetGroups(ctx, "catalog.example", "example.org", "signed"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if groups, err := client.Catalog.Groups(ctx, "catalog.example", "example.org"); err != nil || len(groups) != 1 || groups[0] != "signed" {
+		t.Errorf("unexpected groups %v: %v", groups, err)
+	}
+
+	if err := client.Catalog.RemoveMember(ctx, "catalog.example", "example.org"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := client.Catalog.RemoveMember(ctx, "catalog.example", "example.org"); !errors.Is(err, powerdns.ErrNotCatalogMember) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	members, err = client.Catalog.Members(ctx, "catalog.example")
+	if err != nil || len(members) != 1 || *members[0].Name != "example.com." {
+		t.Errorf("unexpected members %+v: %v", members, err)
+	}
+
+	if metadata, err := client.Metadata.Get(ctx, "example.org", powerdns.MetadataCatalogGroup); err != nil || len(metadata.Metadata) != 0 {
+		t.Errorf("unexpected groups %+v: %v", metadata, err)
+	}
+
+	// The member records of a producer zone are generated on transfer and not stored
+	if zone, _ := s.Zone("catalog.example"); len(zone.RRsets) != 2 {
+		t.Errorf("unexpected catalog zone %+v", zone)
+	}
+}
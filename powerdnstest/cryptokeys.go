@@ -0,0 +1,269 @@
+package powerdnstest
+
+import (
+	"cmp"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+// cryptokeyAlgorithm describes a supported DNSSEC algorithm
+type cryptokeyAlgorithm struct {
+	number uint8
+	name   string
+	bits   uint64
+}
+
+var cryptokeyAlgorithms = map[string]cryptokeyAlgorithm{
+	"ecdsa256":        {13, "ECDSAP256SHA256", 256},
+	"ecdsap256sha256": {13, "ECDSAP256SHA256", 256},
+	"13":              {13, "ECDSAP256SHA256", 256},
+	"ecdsa384":        {14, "ECDSAP384SHA384", 384},
+	"ecdsap384sha384": {14, "ECDSAP384SHA384", 384},
+	"14":              {14, "ECDSAP384SHA384", 384},
+	"ed25519":         {15, "ED25519", 256},
+	"15":              {15, "ED25519", 256},
+}
+
+// generateCryptokey creates a key with a random key pair or, if a private key is given, with an imported one.
+func (s *Server) generateCryptokey(zoneName string, in powerdns.Cryptokey) (*powerdns.Cryptokey, *apiError) {
+	keyType := strings.ToLower(powerdns.StringValue(in.KeyType))
+	if keyType != "ksk" && keyType != "zsk" && keyType != "csk" {
+		return nil, errorf(http.StatusUnprocessableEntity, "Invalid keytype '%s'", keyType)
+	}
+
+	var (
+		algorithm             cryptokeyAlgorithm
+		publicKey, privateKey []byte
+		err                   *apiError
+	)
+	if in.Privatekey != nil {
+		algorithm, publicKey, privateKey, err = importKeyPair(*in.Privatekey)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var ok bool
+		algorithm, ok = cryptokeyAlgorithms[strings.ToLower(powerdns.StringValue(cmp.Or(in.Algorithm, powerdns.String("ecdsa256"))))]
+		if !ok {
+			return nil, errorf(http.StatusUnprocessableEntity, "Unknown algorithm: %s", *in.Algorithm)
+		}
+		publicKey, privateKey = generateKeyPair(algorithm.number)
+	}
+
+	dnskey := powerdns.DNSKEYData{Flags: powerdns.DNSKEYFlagZone | powerdns.DNSKEYFlagSEP, Protocol: 3, Algorithm: algorithm.number, PublicKey: publicKey}
+	if keyType == "zsk" {
+		dnskey.Flags = powerdns.DNSKEYFlagZone
+	}
+
+	s.nextCryptokeyID++
+	key := &powerdns.Cryptokey{
+		Type:       powerdns.String("Cryptokey"),
+		ID:         powerdns.Uint64(s.nextCryptokeyID),
+		KeyType:    powerdns.String(keyType),
+		Active:     powerdns.Bool(powerdns.BoolValue(in.Active)),
+		Published:  powerdns.Bool(in.Published == nil || *in.Published),
+		DNSkey:     powerdns.String(dnskey.String()),
+		Privatekey: powerdns.String(fmt.Sprintf("Private-key-format: v1.2\nAlgorithm: %d (%s)\nPrivateKey: %s\n", algorithm.number, algorithm.name, base64.StdEncoding.EncodeToString(privateKey))),
+		Algorithm:  powerdns.String(algorithm.name),
+		Bits:       powerdns.Uint64(algorithm.bits),
+	}
+	if dnskey.Flags&powerdns.DNSKEYFlagSEP != 0 {
+		key.DS = dsRecords(zoneName, dnskey)
+	}
+
+	return key, nil
+}
+
+func generateKeyPair(algorithm uint8) (publicKey, privateKey []byte) {
+	switch algorithm {
+	case 15:
+		public, private, _ := ed25519.GenerateKey(rand.Reader)
+		return public, private.Seed()
+	case 14:
+		private, _ := ecdh.P384().GenerateKey(rand.Reader)
+		return private.PublicKey().Bytes()[1:], private.Bytes()
+	default:
+		private, _ := ecdh.P256().GenerateKey(rand.Reader)
+		return private.PublicKey().Bytes()[1:], private.Bytes()
+	}
+}
+
+// importKeyPair derives the public key from a private key in ISC format, as written by generateCryptokey.
+func importKeyPair(privatekey string) (algorithm cryptokeyAlgorithm, publicKey, privateKey []byte, err *apiError) {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(privatekey, "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+
+	number, _, _ := strings.Cut(fields["Algorithm"], " ")
+	algorithm, ok := cryptokeyAlgorithms[number]
+	privateKey, _ = base64.StdEncoding.DecodeString(fields["PrivateKey"])
+
+	switch {
+	case !ok:
+	case algorithm.number == 15:
+		if len(privateKey) == ed25519.SeedSize {
+			publicKey = ed25519.NewKeyFromSeed(privateKey).Public().(ed25519.PublicKey)
+		}
+	default:
+		curve := ecdh.P256()
+		if algorithm.number == 14 {
+			curve = ecdh.P384()
+		}
+		if private, curveErr := curve.NewPrivateKey(privateKey); curveErr == nil {
+			publicKey = private.PublicKey().Bytes()[1:]
+		}
+	}
+
+	if publicKey == nil {
+		return algorithm, nil, nil, errorf(http.StatusUnprocessableEntity, "Key could not be parsed. Make sure your key format is correct.")
+	}
+	return algorithm, publicKey, privateKey, nil
+}
+
+// dsDigests are the digest types of the DS records published by PowerDNS
+var dsDigests = []struct {
+	digestType uint8
+	hash       func() hash.Hash
+}{
+	{powerdns.DigestTypeSHA1, sha1.New},
+	{powerdns.DigestTypeSHA256, sha256.New},
+	{powerdns.DigestTypeSHA384, sha512.New384},
+}
+
+// dsRecords computes the DS records with SHA-1, SHA-256 and SHA-384 digests, as published by PowerDNS.
+// It does not use powerdns.DNSKEYData.DS, so that the DS records of the fake are independent of the client under test.
+func dsRecords(zoneName string, dnskey powerdns.DNSKEYData) []string {
+	rdata := binary.BigEndian.AppendUint16(nil, dnskey.Flags)
+	rdata = append(rdata, dnskey.Protocol, dnskey.Algorithm)
+	rdata = append(rdata, dnskey.PublicKey...)
+
+	// Key tag according to RFC 4034, appendix B
+	var keyTag uint32
+	for i, b := range rdata {
+		if i%2 == 0 {
+			keyTag += uint32(b) << 8
+		} else {
+			keyTag += uint32(b)
+		}
+	}
+	keyTag += keyTag >> 16
+
+	var owner []byte
+	for _, label := range strings.Split(canonicalName(zoneName), ".") {
+		owner = append(owner, byte(len(label)))
+		owner = append(owner, label...)
+	}
+
+	ds := make([]string, len(dsDigests))
+	for i, digest := range dsDigests {
+		h := digest.hash()
+		h.Write(owner)
+		h.Write(rdata)
+		ds[i] = fmt.Sprintf("%d %d %d %x", uint16(keyTag), dnskey.Algorithm, digest.digestType, h.Sum(nil))
+	}
+	return ds
+}
+
+func (s *Server) lookupCryptokey(r *http.Request) (*zone, int, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	id, parseErr := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	i := slices.IndexFunc(z.cryptokeys, func(key *powerdns.Cryptokey) bool {
+		return parseErr == nil && *key.ID == id
+	})
+	if i < 0 {
+		return nil, 0, errorf(http.StatusNotFound, "Could not find cryptokey with id %s", r.PathValue("id"))
+	}
+
+	return z, i, nil
+}
+
+func (s *Server) listCryptokeys(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	keys := make([]powerdns.Cryptokey, len(z.cryptokeys))
+	for i, key := range z.cryptokeys {
+		keys[i] = *key
+		keys[i].Privatekey = nil
+	}
+	return http.StatusOK, keys, nil
+}
+
+func (s *Server) postCryptokey(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var in powerdns.Cryptokey
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+
+	key, err := s.generateCryptokey(z.apex(), in)
+	if err != nil {
+		return 0, nil, err
+	}
+	z.cryptokeys = append(z.cryptokeys, key)
+	return http.StatusCreated, key, nil
+}
+
+func (s *Server) getCryptokey(r *http.Request) (int, any, *apiError) {
+	z, i, err := s.lookupCryptokey(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, z.cryptokeys[i], nil
+}
+
+func (s *Server) putCryptokey(r *http.Request) (int, any, *apiError) {
+	z, i, err := s.lookupCryptokey(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var in powerdns.Cryptokey
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+	if in.Active == nil {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "Key 'active' not present or not a Boolean")
+	}
+
+	z.cryptokeys[i].Active = in.Active
+	z.cryptokeys[i].Published = powerdns.Bool(in.Published == nil || *in.Published)
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) deleteCryptokey(r *http.Request) (int, any, *apiError) {
+	z, i, err := s.lookupCryptokey(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	z.cryptokeys = slices.Delete(z.cryptokeys, i, i+1)
+	return http.StatusNoContent, nil, nil
+}
@@ -0,0 +1,180 @@
+package powerdnstest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func TestCryptokeys(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+
+	testCases := []struct {
+		cryptokey powerdns.Cryptokey
+		algorithm string
+		bits      uint64
+		dnskey    string
+		ds        int
+	}{
+		{powerdns.Cryptokey{KeyType: powerdns.String("csk"), Active: powerdns.Bool(true)}, "ECDSAP256SHA256", 256, "257 3 13 ", 3},
+		{powerdns.Cryptokey{KeyType: powerdns.String("KSK"), Algorithm: powerdns.String("ecdsa384")}, "ECDSAP384SHA384", 384, "257 3 14 ", 3},
+		{powerdns.Cryptokey{KeyType: powerdns.String("zsk"), Algorithm: powerdns.String("ED25519"), Published: powerdns.Bool(false)}, "ED25519", 256, "256 3 15 ", 0},
+	}
+
+	for _, tc := range testCases {
+		key, err := client.Cryptokeys.Create(ctx, "example.com", &tc.cryptokey)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := key.VerifyDS("example.com"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if *key.Algorithm != tc.algorithm || *key.Bits != tc.bits || !strings.HasPrefix(*key.DNSkey, tc.dnskey) || len(key.DS) != tc.ds || !strings.Contains(*key.Privatekey, "PrivateKey: ") || *key.Published != powerdns.BoolValue(cmp.Or(tc.cryptokey.Published, powerdns.Bool(true))) {
+			t.Errorf("unexpected key %+v", key)
+		}
+	}
+
+	keys, err := client.Cryptokeys.List(ctx, "example.com")
+	if err != nil || len(keys) != 3 || keys[0].Privatekey != nil {
+		t.Errorf("unexpected keys %+v: %v", keys, err)
+	}
+	if zone, _ := s.Zone("example.com"); !*zone.DNSsec {
+		t.Error("DNSSEC has not been enabled by an active key")
+	}
+
+	key, err := client.Cryptokeys.Get(ctx, "example.com", 2)
+	if err != nil || *key.KeyType != "ksk" || *key.Active || key.Privatekey == nil {
+		t.Errorf("unexpected key %+v: %v", key, err)
+	}
+
+	if err := client.Cryptokeys.Activate(ctx, "example.com", 2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := client.Cryptokeys.Unpublish(ctx, "example.com", 2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if key, _ := client.Cryptokeys.Get(ctx, "example.com", 2); !*key.Active || *key.Published {
+		t.Errorf("unexpected key %+v", key)
+	}
+	if err := client.Cryptokeys.Change(ctx, "example.com", 2, &powerdns.Cryptokey{Active: powerdns.Bool(false)}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if key, _ := client.Cryptokeys.Get(ctx, "example.com", 2); *key.Active || !*key.Published {
+		t.Errorf("unexpected key %+v", key)
+	}
+
+	if err := client.Cryptokeys.Delete(ctx, "example.com", 1); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if keys, _ := client.Cryptokeys.List(ctx, "example.com"); len(keys) != 2 {
+		t.Errorf("unexpected keys %+v", keys)
+	}
+}
+
+func TestCryptokeyDS(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.net")
+
+	// Examples from RFC 6605, section 6
+	testCases := []struct {
+		privatekey string
+		dnskey     string
+		ds         string
+	}{
+		{
+			"Private-key-format: v1.2\nAlgorithm: 13 (ECDSAP256SHA256)\nPrivateKey: GU6SnQ/Ou+xC5RumuIUIuJZteXT2z0O/ok1s38Et6mQ=\n",
+			"257 3 13 GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA==",
+			"55648 13 2 b4c8c1fe2e7477127b27115656ad6256f424625bf5c1e2770ce6d6e37df61d17",
+		},
+		{
+			"Private-key-format: v1.2\nAlgorithm: 14 (ECDSAP384SHA384)\nPrivateKey: WURgWHCcYIYUPWgeLmiPY2DJJk02vgrmTfitxgqcL4vwW7BOrbawVmVe0d9V94SR\n",
+			"257 3 14 xKYaNhWdGOfJ+nPrL8/arkwf2EY3MDJ+SErKivBVSum1w/egsXvSADtNJhyem5RCOpgQ6K8X1DRSEkrbYQ+OB+v8/uX45NBwY8rp65F6Glur8I/mlVNgF6W/qTI37m40",
+			"10771 14 4 72d7b62976ce06438e9c0bf319013cf801f09ecc84b8d7e9495f27e305c6a9b0563a9b5f4d288405c3008a946df983d6",
+		},
+	}
+
+	for _, tc := range testCases {
+		key, err := client.Cryptokeys.Create(ctx, "EXAMPLE.net", &powerdns.Cryptokey{KeyType: powerdns.String("ksk"), Privatekey: powerdns.String(tc.privatekey)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *key.DNSkey != tc.dnskey || !slices.Contains(key.DS, tc.ds) {
+			t.Errorf("unexpected key %s %v", *key.DNSkey, key.DS)
+		}
+	}
+}
+
+func TestImportCryptokey(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+	addTestZone(t, s, "example.org")
+
+	for _, algorithm := range []string{"ecdsa256", "ecdsa384", "ed25519"} {
+		generated, err := client.Cryptokeys.Create(ctx, "example.com", &powerdns.Cryptokey{KeyType: powerdns.String("csk"), Algorithm: powerdns.String(algorithm)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		imported, err := client.Cryptokeys.Create(ctx, "example.org", &powerdns.Cryptokey{KeyType: powerdns.String("csk"), Privatekey: generated.Privatekey})
+		if err != nil || *imported.DNSkey != *generated.DNSkey || *imported.Privatekey != *generated.Privatekey || imported.DS[0] == generated.DS[0] {
+			t.Errorf("unexpected key %+v: %v", imported, err)
+		}
+	}
+
+	for _, privatekey := range []string{
+		"secret",
+		"Private-key-format: v1.2\nAlgorithm: 8 (RSASHA256)\nPrivateKey: AAAA\n",
+		"Private-key-format: v1.2\nAlgorithm: 13 (ECDSAP256SHA256)\nPrivateKey: AAAA\n",
+		"Private-key-format: v1.2\nAlgorithm: 15 (ED25519)\nPrivateKey: AAAA\n",
+	} {
+		if _, err := client.Cryptokeys.Create(ctx, "example.org", &powerdns.Cryptokey{KeyType: powerdns.String("csk"), Privatekey: powerdns.String(privatekey)}); !errors.Is(err, powerdns.ErrValidation) {
+			t.Errorf("unexpected error for %q: %v", privatekey, err)
+		}
+	}
+}
+
+func TestCryptokeyErrors(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+	if _, err := client.Cryptokeys.Create(ctx, "example.com", &powerdns.Cryptokey{KeyType: powerdns.String("csk")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		method, path, body string
+		wantStatus         int
+	}{
+		{http.MethodGet, "/zones/example.org./cryptokeys", "", http.StatusNotFound},
+		{http.MethodPost, "/zones/example.org./cryptokeys", "{}", http.StatusNotFound},
+		{http.MethodPost, "/zones/example.com./cryptokeys", "{", http.StatusBadRequest},
+		{http.MethodPost, "/zones/example.com./cryptokeys", `{"keytype": "rsa"}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/zones/example.com./cryptokeys", `{"keytype": "csk", "algorithm": "rsasha256"}`, http.StatusUnprocessableEntity},
+		{http.MethodGet, "/zones/example.org./cryptokeys/1", "", http.StatusNotFound},
+		{http.MethodGet, "/zones/example.com./cryptokeys/2", "", http.StatusNotFound},
+		{http.MethodGet, "/zones/example.com./cryptokeys/first", "", http.StatusNotFound},
+		{http.MethodPut, "/zones/example.com./cryptokeys/2", `{"active": true}`, http.StatusNotFound},
+		{http.MethodPut, "/zones/example.com./cryptokeys/1", "{", http.StatusBadRequest},
+		{http.MethodPut, "/zones/example.com./cryptokeys/1", "{}", http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		if status, body := rawRequest(t, s, tc.method, tc.path, tc.body); status != tc.wantStatus {
+			t.Errorf("%s %s: unexpected response %d %s", tc.method, tc.path, status, body)
+		}
+	}
+
+	if err := client.Cryptokeys.Delete(ctx, "example.com", 2); !errors.Is(err, powerdns.ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package powerdnstest
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+// readOnlyMetadataKinds cannot be modified by the metadata endpoints, but only by changing the zone
+var readOnlyMetadataKinds = []powerdns.MetadataKind{powerdns.MetadataNSEC3Param, "NSEC3NARROW", powerdns.MetadataPresigned, powerdns.MetadataLuaAXFRScript}
+
+func metadataView(kind powerdns.MetadataKind, values []string) powerdns.Metadata {
+	return powerdns.Metadata{Kind: powerdns.MetadataKindPtr(kind), Metadata: slices.Clone(values)}
+}
+
+func (s *Server) listMetadata(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	metadata := make([]powerdns.Metadata, 0, len(z.metadata))
+	for kind, values := range z.metadata {
+		metadata = append(metadata, metadataView(kind, values))
+	}
+	slices.SortFunc(metadata, func(a, b powerdns.Metadata) int {
+		return cmp.Compare(*a.Kind, *b.Kind)
+	})
+
+	return http.StatusOK, metadata, nil
+}
+
+// decodeMetadata decodes the request body and returns the kind from the path or, if it is absent, from the body.
+func decodeMetadata(r *http.Request) (powerdns.MetadataKind, []string, *apiError) {
+	var in powerdns.Metadata
+	if err := decodeBody(r, &in); err != nil {
+		return "", nil, err
+	}
+
+	kind := powerdns.MetadataKind(r.PathValue("kind"))
+	if kind == "" && in.Kind != nil {
+		kind = *in.Kind
+	}
+	if kind == "" {
+		return "", nil, errorf(http.StatusUnprocessableEntity, "Key 'kind' not present or not a String")
+	}
+	if slices.Contains(readOnlyMetadataKinds, kind) {
+		return "", nil, errorf(http.StatusUnprocessableEntity, "Setting metadata kind '%s' is not allowed through the API", kind)
+	}
+
+	return kind, in.Metadata, nil
+}
+
+func (s *Server) postMetadata(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	kind, values, err := decodeMetadata(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, value := range values {
+		if !slices.Contains(z.metadata[kind], value) {
+			z.metadata[kind] = append(z.metadata[kind], value)
+		}
+	}
+	return http.StatusCreated, metadataView(kind, z.metadata[kind]), nil
+}
+
+func (s *Server) getMetadata(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	kind := powerdns.MetadataKind(r.PathValue("kind"))
+	return http.StatusOK, metadataView(kind, z.metadata[kind]), nil
+}
+
+func (s *Server) putMetadata(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	kind, values, err := decodeMetadata(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(values) == 0 {
+		delete(z.metadata, kind)
+	} else {
+		z.metadata[kind] = slices.Clone(values)
+	}
+	return http.StatusOK, metadataView(kind, values), nil
+}
+
+func (s *Server) deleteMetadata(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	delete(z.metadata, powerdns.MetadataKind(r.PathValue("kind")))
+	return http.StatusNoContent, nil, nil
+}
@@ -0,0 +1,87 @@
+package powerdnstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func TestMetadata(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+
+	metadata, err := client.Metadata.Create(ctx, "example.com", powerdns.MetadataAllowAXFRFrom, []string{"192.0.2.1", "192.0.2.1"})
+	if err != nil || len(metadata.Metadata) != 1 {
+		t.Errorf("unexpected metadata %+v: %v", metadata, err)
+	}
+	metadata, err = client.Metadata.Create(ctx, "example.com", powerdns.MetadataAllowAXFRFrom, []string{"192.0.2.2"})
+	if err != nil || len(metadata.Metadata) != 2 {
+		t.Errorf("unexpected metadata %+v: %v", metadata, err)
+	}
+
+	metadata, err = client.Metadata.Set(ctx, "example.com", powerdns.MetadataAllowAXFRFrom, []string{"192.0.2.3"})
+	if err != nil || len(metadata.Metadata) != 1 {
+		t.Errorf("unexpected metadata %+v: %v", metadata, err)
+	}
+	metadata, err = client.Metadata.Get(ctx, "example.com", powerdns.MetadataAllowAXFRFrom)
+	if err != nil || metadata.Metadata[0] != "192.0.2.3" {
+		t.Errorf("unexpected metadata %+v: %v", metadata, err)
+	}
+
+	list, err := client.Metadata.List(ctx, "example.com")
+	if err != nil || len(list) != 2 || *list[0].Kind != powerdns.MetadataAllowAXFRFrom || *list[1].Kind != powerdns.MetadataSOAEditAPI {
+		t.Errorf("unexpected metadata %+v: %v", list, err)
+	}
+
+	if _, err := client.Metadata.Set(ctx, "example.com", powerdns.MetadataAllowAXFRFrom, []string{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := client.Metadata.Delete(ctx, "example.com", powerdns.MetadataSOAEditAPI); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if list, err := client.Metadata.List(ctx, "example.com"); err != nil || len(list) != 0 {
+		t.Errorf("unexpected metadata %+v: %v", list, err)
+	}
+}
+
+func TestMetadataErrors(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+
+	if _, err := client.Metadata.List(ctx, "example.org"); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := client.Metadata.Create(ctx, "example.org", powerdns.MetadataAllowAXFRFrom, nil); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := client.Metadata.Get(ctx, "example.org", powerdns.MetadataAllowAXFRFrom); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := client.Metadata.Set(ctx, "example.org", powerdns.MetadataAllowAXFRFrom, nil); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := client.Metadata.Delete(ctx, "example.org", powerdns.MetadataAllowAXFRFrom); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := client.Metadata.Create(ctx, "example.com", powerdns.MetadataPresigned, []string{"1"}); !errors.Is(err, powerdns.ErrValidation) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := client.Metadata.Set(ctx, "example.com", powerdns.MetadataNSEC3Param, []string{"1 0 0 -"}); !errors.Is(err, powerdns.ErrValidation) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if status, body := rawRequest(t, s, http.MethodPost, "/zones/example.com./metadata", `{"metadata": ["1"]}`); status != http.StatusUnprocessableEntity {
+		t.Errorf("unexpected response %d %s", status, body)
+	}
+	if status, _ := rawRequest(t, s, http.MethodPost, "/zones/example.com./metadata", "{"); status != http.StatusBadRequest {
+		t.Errorf("unexpected status %d", status)
+	}
+	if status, _ := rawRequest(t, s, http.MethodPut, "/zones/example.com./metadata/ALLOW-AXFR-FROM", "{"); status != http.StatusBadRequest {
+		t.Errorf("unexpected status %d", status)
+	}
+}
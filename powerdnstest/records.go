@@ -0,0 +1,173 @@
+package powerdnstest
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+// patchZone applies RRset changes. Either all changes are applied or none.
+func (s *Server) patchZone(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var in powerdns.RRsets
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+
+	rrSets := slices.Clone(z.rrSets)
+	seen := make(map[string]bool, len(in.Sets))
+	soaChanged := false
+	for _, change := range in.Sets {
+		if change.Name == nil || change.Type == nil {
+			return 0, nil, errorf(http.StatusUnprocessableEntity, "RRset has no name or type")
+		}
+		change.Name = powerdns.String(canonicalName(*change.Name))
+		name, recordType := *change.Name, *change.Type
+
+		key := name + "/" + string(recordType)
+		if seen[key] {
+			return 0, nil, errorf(http.StatusUnprocessableEntity, "Duplicate RRset %s IN %s with changetype: %s", name, recordType, powerdns.StringValue((*string)(change.ChangeType)))
+		}
+		seen[key] = true
+		soaChanged = soaChanged || recordType == powerdns.RRTypeSOA
+
+		if err := validateRRset(z.apex(), change); err != nil {
+			return 0, nil, err
+		}
+
+		i := slices.IndexFunc(rrSets, func(rrSet powerdns.RRset) bool {
+			return *rrSet.Name == name && *rrSet.Type == recordType
+		})
+
+		switch powerdns.StringValue((*string)(change.ChangeType)) {
+		case string(powerdns.ChangeTypeDelete):
+			if i >= 0 {
+				rrSets = slices.Delete(rrSets, i, i+1)
+			}
+		case string(powerdns.ChangeTypeReplace):
+			rrSet := powerdns.RRset{Name: &name, Type: &recordType}
+			if i >= 0 {
+				rrSet = rrSets[i]
+				rrSets = slices.Delete(rrSets, i, i+1)
+			}
+			if err := s.replaceRRset(&rrSet, change); err != nil {
+				return 0, nil, err
+			}
+			if len(rrSet.Records) > 0 || len(rrSet.Comments) > 0 {
+				rrSets = append(rrSets, rrSet)
+			}
+		default:
+			return 0, nil, errorf(http.StatusUnprocessableEntity, "Changetype not understood")
+		}
+	}
+
+	if err := validateRRsets(rrSets); err != nil {
+		return 0, nil, err
+	}
+
+	z.rrSets = rrSets
+	if !soaChanged {
+		s.bumpSerial(z)
+	}
+	return http.StatusNoContent, nil, nil
+}
+
+// replaceRRset replaces the records and comments of rrSet if they are present in change.
+func (s *Server) replaceRRset(rrSet *powerdns.RRset, change powerdns.RRset) *apiError {
+	if change.Records != nil {
+		if len(change.Records) > 0 && change.TTL == nil {
+			return errorf(http.StatusUnprocessableEntity, "Key 'ttl' not present or not an Integer")
+		}
+
+		rrSet.TTL = change.TTL
+		rrSet.Records = make([]powerdns.Record, len(change.Records))
+		for i, record := range change.Records {
+			rrSet.Records[i] = powerdns.Record{Content: record.Content, Disabled: powerdns.Bool(powerdns.BoolValue(record.Disabled))}
+		}
+	}
+
+	if change.Comments != nil {
+		rrSet.Comments = make([]powerdns.Comment, len(change.Comments))
+		for i, comment := range change.Comments {
+			if comment.ModifiedAt == nil {
+				comment.ModifiedAt = powerdns.Uint64(uint64(s.now().Unix()))
+			}
+			rrSet.Comments[i] = comment
+		}
+	}
+
+	return nil
+}
+
+// validateRRset checks a single RRset, which must belong to the zone and must not contain duplicate records.
+func validateRRset(zoneName string, rrSet powerdns.RRset) *apiError {
+	if !inZone(*rrSet.Name, zoneName) {
+		return errorf(http.StatusUnprocessableEntity, "RRset %s IN %s: Name is out of zone", *rrSet.Name, *rrSet.Type)
+	}
+
+	seen := make(map[string]bool, len(rrSet.Records))
+	for _, record := range rrSet.Records {
+		content := powerdns.StringValue(record.Content)
+		if seen[content] {
+			return errorf(http.StatusUnprocessableEntity, "Duplicate record in RRset %s IN %s with content \"%s\"", *rrSet.Name, *rrSet.Type, content)
+		}
+		seen[content] = true
+	}
+
+	return nil
+}
+
+// validateRRsets rejects CNAME RRsets with more than one record or next to other data.
+func validateRRsets(rrSets []powerdns.RRset) *apiError {
+	types := make(map[string][]powerdns.RRType)
+	for _, rrSet := range rrSets {
+		if *rrSet.Type == powerdns.RRTypeCNAME && len(rrSet.Records) > 1 {
+			return errorf(http.StatusUnprocessableEntity, "RRset %s IN CNAME has more than one record", *rrSet.Name)
+		}
+		if len(rrSet.Records) > 0 {
+			types[*rrSet.Name] = append(types[*rrSet.Name], *rrSet.Type)
+		}
+	}
+
+	for name, recordTypes := range types {
+		if len(recordTypes) > 1 && slices.Contains(recordTypes, powerdns.RRTypeCNAME) {
+			return errorf(http.StatusUnprocessableEntity, "RRset %s IN CNAME: Conflicts with pre-existing RRset", name)
+		}
+	}
+
+	return nil
+}
+
+// bumpSerial increases the SOA serial according to the SOA-EDIT-API setting of the zone.
+// SOA-EDIT and SOA-EDIT-INCREASE are treated like DEFAULT, which generates a serial of the form YYYYMMDD01.
+func (s *Server) bumpSerial(z *zone) {
+	fields := z.soaFields()
+	if fields == nil {
+		return
+	}
+
+	serial := z.serial()
+	switch z.metadataValue(powerdns.MetadataSOAEditAPI) {
+	case "", "OFF":
+		return
+	case "INCREASE":
+		serial++
+	case "EPOCH":
+		serial = max(uint32(s.now().Unix()), serial+1)
+	default:
+		year, month, day := s.now().UTC().Date()
+		serial = max(uint32(year*1000000+int(month)*10000+day*100+1), serial+1)
+	}
+
+	fields[2] = strconv.FormatUint(uint64(serial), 10)
+	soa := z.findRRset(z.apex(), powerdns.RRTypeSOA)
+	soa.Records = slices.Clone(soa.Records)
+	soa.Records[0].Content = powerdns.String(strings.Join(fields, " "))
+}
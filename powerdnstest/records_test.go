@@ -0,0 +1,189 @@
+package powerdnstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func TestPatchRecords(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+
+	if err := client.Records.Add(ctx, "example.com", "www.example.com", powerdns.RRTypeA, 60, []string{"192.0.2.1", "192.0.2.2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Records.AddRecord(ctx, "example.com", "www.example.com", powerdns.RRTypeA, 60, "192.0.2.3"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Records.RemoveRecord(ctx, "example.com", "www.example.com", powerdns.RRTypeA, "192.0.2.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rrSets, err := client.Records.Get(ctx, "example.com", "www.example.com", powerdns.RRTypePtr(powerdns.RRTypeA))
+	if err != nil || len(rrSets) != 1 || len(rrSets[0].Records) != 2 || *rrSets[0].Records[0].Content != "192.0.2.2" {
+		t.Errorf("unexpected RRsets %+v: %v", rrSets, err)
+	}
+
+	zone, _ := s.Zone("example.com")
+	if *zone.Serial != 2024010204 {
+		t.Errorf("unexpected serial %d", *zone.Serial)
+	}
+
+	if err := client.Records.Delete(ctx, "example.com", "www.example.com", powerdns.RRTypeA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Records.Delete(ctx, "example.com", "www.example.com", powerdns.RRTypeA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if zone, _ := s.Zone("example.com"); len(zone.RRsets) != 2 {
+		t.Errorf("unexpected RRsets %+v", zone.RRsets)
+	}
+}
+
+func TestPatchComments(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+
+	changeSet := client.Records.NewChangeSet("example.com")
+	_ = changeSet.SetComments("example.com", powerdns.RRTypeNS, powerdns.Comment{Content: powerdns.String("first")}, powerdns.Comment{Content: powerdns.String("second"), ModifiedAt: powerdns.Uint64(1)})
+	_ = changeSet.SetComments("comment.example.com", powerdns.RRTypeTXT, powerdns.Comment{Content: powerdns.String("no records")})
+	if err := changeSet.Commit(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rrSets, _ := client.Records.Get(ctx, "example.com", "example.com", powerdns.RRTypePtr(powerdns.RRTypeNS))
+	if len(rrSets[0].Records) != 2 || len(rrSets[0].Comments) != 2 || *rrSets[0].Comments[0].ModifiedAt != uint64(testTime.Unix()) || *rrSets[0].Comments[1].ModifiedAt != 1 {
+		t.Errorf("unexpected RRset %+v", rrSets[0])
+	}
+
+	rrSets, _ = client.Records.Get(ctx, "example.com", "comment.example.com", nil)
+	if len(rrSets) != 1 || len(rrSets[0].Records) != 0 {
+		t.Errorf("unexpected RRsets %+v", rrSets)
+	}
+
+	if err := client.Records.Patch(ctx, "example.com", &powerdns.RRsets{Sets: []powerdns.RRset{{Name: powerdns.String("comment.example.com."), Type: powerdns.RRTypePtr(powerdns.RRTypeTXT), ChangeType: powerdns.ChangeTypePtr(powerdns.ChangeTypeReplace), Comments: []powerdns.Comment{}}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rrSets, _ = client.Records.Get(ctx, "example.com", "comment.example.com", nil); len(rrSets) != 0 {
+		t.Errorf("unexpected RRsets %+v", rrSets)
+	}
+}
+
+func TestPatchErrors(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+	if err := client.Records.Add(ctx, "example.com", "www.example.com", powerdns.RRTypeA, 60, []string{"192.0.2.1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{"no name", `{"rrsets": [{"type": "A", "changetype": "DELETE"}]}`},
+		{"duplicate", `{"rrsets": [{"name": "www.example.com.", "type": "A", "changetype": "DELETE"}, {"name": "WWW.example.com.", "type": "A", "changetype": "DELETE"}]}`},
+		{"out of zone", `{"rrsets": [{"name": "www.example.org.", "type": "A", "changetype": "DELETE"}]}`},
+		{"duplicate record", `{"rrsets": [{"name": "www.example.com.", "type": "A", "ttl": 60, "changetype": "REPLACE", "records": [{"content": "192.0.2.1"}, {"content": "192.0.2.1"}]}]}`},
+		{"missing TTL", `{"rrsets": [{"name": "www.example.com.", "type": "A", "changetype": "REPLACE", "records": [{"content": "192.0.2.1"}]}]}`},
+		{"changetype", `{"rrsets": [{"name": "www.example.com.", "type": "A", "changetype": "UPSERT"}]}`},
+		{"CNAME conflict", `{"rrsets": [{"name": "www.example.com.", "type": "CNAME", "ttl": 60, "changetype": "REPLACE", "records": [{"content": "example.com."}]}]}`},
+		{"CNAME records", `{"rrsets": [{"name": "alias.example.com.", "type": "CNAME", "ttl": 60, "changetype": "REPLACE", "records": [{"content": "a.example.com."}, {"content": "b.example.com."}]}]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if status, body := rawRequest(t, s, http.MethodPatch, "/zones/example.com.", tc.body); status != http.StatusUnprocessableEntity {
+				t.Errorf("unexpected response %d %s", status, body)
+			}
+		})
+	}
+
+	if status, _ := rawRequest(t, s, http.MethodPatch, "/zones/example.com.", "{"); status != http.StatusBadRequest {
+		t.Errorf("unexpected status %d", status)
+	}
+	if err := client.Records.Delete(ctx, "example.org", "www.example.org", powerdns.RRTypeA); !errors.Is(err, powerdns.ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := client.Records.Change(ctx, "example.com", "www.example.com", powerdns.RRTypeCNAME, 60, []string{"example.com"})
+	if !errors.Is(err, powerdns.ErrRRsetConflict) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if zone, _ := s.Zone("example.com"); *zone.Serial != 2024010202 {
+		t.Errorf("failed patches have been applied, serial %d", *zone.Serial)
+	}
+}
+
+func TestSerialBumping(t *testing.T) {
+	testCases := []struct {
+		soaEditAPI string
+		serial     string
+		wantSerial uint32
+	}{
+		{"DEFAULT", "1", 2024010201},
+		{"DEFAULT", "2024010205", 2024010206},
+		{"INCREASE", "7", 8},
+		{"EPOCH", "7", uint32(testTime.Unix())},
+		{"OFF", "7", 7},
+		{"", "7", 7},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.soaEditAPI, func(t *testing.T) {
+			s, client := newTestServer(t)
+			ctx := context.Background()
+			if err := s.AddZone(powerdns.Zone{Name: powerdns.String("example.com."), Kind: powerdns.ZoneKindPtr(powerdns.NativeZoneKind), SOAEditAPI: powerdns.String("OFF")}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := client.Records.Change(ctx, "example.com", "example.com", powerdns.RRTypeSOA, 3600, []string{"ns1.example.com. hostmaster.example.com. " + tc.serial + " 10800 3600 604800 3600"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := client.Zones.Change(ctx, "example.com", &powerdns.Zone{SOAEditAPI: powerdns.String(tc.soaEditAPI)}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err := client.Records.Add(ctx, "example.com", "www.example.com", powerdns.RRTypeA, 60, []string{"192.0.2.1"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if zone, _ := s.Zone("example.com"); *zone.Serial != tc.wantSerial {
+				t.Errorf("unexpected serial %d", *zone.Serial)
+			}
+		})
+	}
+}
+
+func TestSerialWithoutSOA(t *testing.T) {
+	s, client := newTestServer(t, WithClock(time.Now))
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+
+	if err := client.Records.Change(ctx, "example.com", "example.com", powerdns.RRTypeSOA, 3600, []string{"invalid"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Records.Add(ctx, "example.com", "www.example.com", powerdns.RRTypeA, 60, []string{"192.0.2.1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if zone, _ := s.Zone("example.com"); *zone.Serial != 0 {
+		t.Errorf("unexpected serial %d", *zone.Serial)
+	}
+
+	if err := client.Records.Delete(ctx, "example.com", "example.com", powerdns.RRTypeSOA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Records.Add(ctx, "example.com", "www.example.com", powerdns.RRTypeA, 60, []string{"192.0.2.2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if zone, _ := s.Zone("example.com"); *zone.Serial != 0 {
+		t.Errorf("unexpected serial %d", *zone.Serial)
+	}
+}
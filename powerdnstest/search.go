@@ -0,0 +1,88 @@
+package powerdnstest
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+// compileSearchQuery converts a query with the wildcards * and ? into a case-insensitive regular expression.
+func compileSearchQuery(query string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?i)^")
+	for _, c := range query {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func (s *Server) searchData(r *http.Request) (int, any, *apiError) {
+	query := r.URL.Query()
+	pattern := compileSearchQuery(query.Get("q"))
+	maxResults, err := strconv.Atoi(query.Get("max"))
+	if err != nil || maxResults < 0 {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "'max' must be a non-negative integer")
+	}
+	objectType := powerdns.SearchObjectType(query.Get("object_type"))
+	if objectType == "" {
+		objectType = powerdns.SearchObjectTypeAll
+	}
+	wants := func(t powerdns.SearchObjectType) bool {
+		return objectType == powerdns.SearchObjectTypeAll || objectType == t
+	}
+
+	names := make([]string, 0, len(s.zones))
+	for name := range s.zones {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	results := make([]powerdns.SearchResult, 0)
+	for _, name := range names {
+		z := s.zones[name]
+		if wants(powerdns.SearchObjectTypeZone) && pattern.MatchString(name) {
+			results = append(results, powerdns.SearchResult{Name: powerdns.String(name), ObjectType: powerdns.String(string(powerdns.SearchObjectTypeZone)), ZoneID: powerdns.String(name)})
+		}
+
+		for _, rrSet := range s.zoneView(z, true).RRsets {
+			result := powerdns.SearchResult{Name: rrSet.Name, Type: powerdns.String(string(*rrSet.Type)), Zone: powerdns.String(name), ZoneID: powerdns.String(name)}
+			if wants(powerdns.SearchObjectTypeRecord) {
+				for _, record := range rrSet.Records {
+					if pattern.MatchString(*rrSet.Name) || pattern.MatchString(*record.Content) {
+						recordResult := result
+						recordResult.ObjectType = powerdns.String(string(powerdns.SearchObjectTypeRecord))
+						recordResult.Content, recordResult.Disabled, recordResult.TTL = record.Content, record.Disabled, rrSet.TTL
+						results = append(results, recordResult)
+					}
+				}
+			}
+			if wants(powerdns.SearchObjectTypeComment) {
+				for _, comment := range rrSet.Comments {
+					if pattern.MatchString(*rrSet.Name) || pattern.MatchString(powerdns.StringValue(comment.Content)) {
+						commentResult := result
+						commentResult.ObjectType = powerdns.String(string(powerdns.SearchObjectTypeComment))
+						commentResult.Content = comment.Content
+						results = append(results, commentResult)
+					}
+				}
+			}
+		}
+	}
+
+	if len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	return http.StatusOK, results, nil
+}
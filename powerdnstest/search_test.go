@@ -0,0 +1,53 @@
+package powerdnstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func TestSearchData(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+	addTestZone(t, s, "example.org")
+	changeSet := client.Records.NewChangeSet("example.com")
+	if err := changeSet.Replace("www.example.com", powerdns.RRTypeA, 60, []string{"192.0.2.1"}, powerdns.WithComments(powerdns.Comment{Content: powerdns.String("web server")})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := changeSet.Commit(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		query      string
+		max        int
+		objectType powerdns.SearchObjectType
+		want       int
+	}{
+		{"example.???.", 100, powerdns.SearchObjectTypeZone, 2},
+		{"EXAMPLE.*", 100, "", 8},
+		{"example.*", 1, powerdns.SearchObjectTypeAll, 1},
+		{"192.0.2.?", 100, powerdns.SearchObjectTypeRecord, 1},
+		{"*server", 100, powerdns.SearchObjectTypeComment, 1},
+		{"www.example.com.", 100, powerdns.SearchObjectTypeComment, 1},
+		{"ns?.example.com.", 100, powerdns.SearchObjectTypeZone, 0},
+	}
+
+	for _, tc := range testCases {
+		results, err := client.Search.Data(ctx, tc.query, tc.max, tc.objectType)
+		if err != nil || len(results) != tc.want {
+			t.Errorf("unexpected results for %q: %+v: %v", tc.query, results, err)
+		}
+	}
+
+	if _, err := client.Search.Data(ctx, "*", -1, ""); !errors.Is(err, powerdns.ErrValidation) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if status, _ := rawRequest(t, s, http.MethodGet, "/search-data?q=*", ""); status != http.StatusUnprocessableEntity {
+		t.Errorf("unexpected status %d", status)
+	}
+}
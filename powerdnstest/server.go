@@ -0,0 +1,270 @@
+// Package powerdnstest provides an in-memory fake of the PowerDNS authoritative HTTP API.
+// It allows testing code which uses the powerdns package without running PowerDNS itself.
+package powerdnstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+// DefaultAPIKey is the API key accepted by a Server, unless WithAPIKey is used
+const DefaultAPIKey = "powerdnstest"
+
+// DefaultVHost is the name of the only server served by a Server, unless WithVHost is used
+const DefaultVHost = "localhost"
+
+// Server is a fake PowerDNS authoritative server backed by in-memory state.
+// Zones, RRsets, metadata, cryptokeys, TSIG keys, autoprimaries, views and networks are kept as long as the server is running.
+type Server struct {
+	*httptest.Server
+
+	// APIKey is the expected value of the X-API-Key header
+	APIKey string
+
+	// VHost is the name of the server
+	VHost string
+
+	mu              sync.Mutex
+	now             func() time.Time
+	zones           map[string]*zone
+	tsigKeys        map[string]*powerdns.TSIGKey
+	autoprimaries   []powerdns.Autoprimary
+	views           map[string][]string
+	networks        map[netip.Prefix]string
+	statistics      []powerdns.Statistic
+	config          []powerdns.ConfigSetting
+	nextCryptokeyID uint64
+}
+
+// Option is a functional option for NewServer.
+type Option func(*Server)
+
+// WithAPIKey is an option for NewServer to set the expected API key.
+func WithAPIKey(key string) Option {
+	return func(s *Server) {
+		s.APIKey = key
+	}
+}
+
+// WithVHost is an option for NewServer to set the name of the server.
+func WithVHost(vHost string) Option {
+	return func(s *Server) {
+		s.VHost = vHost
+	}
+}
+
+// WithClock is an option for NewServer to set the clock used for SOA serials and comment timestamps.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// WithStatistics is an option for NewServer to replace the default statistics.
+func WithStatistics(statistics ...powerdns.Statistic) Option {
+	return func(s *Server) {
+		s.statistics = statistics
+	}
+}
+
+// WithConfig is an option for NewServer to set the configuration settings.
+func WithConfig(settings ...powerdns.ConfigSetting) Option {
+	return func(s *Server) {
+		s.config = settings
+	}
+}
+
+// NewServer starts a fake PowerDNS server. It should be closed by calling Close.
+func NewServer(options ...Option) *Server {
+	s := &Server{
+		APIKey:        DefaultAPIKey,
+		VHost:         DefaultVHost,
+		now:           time.Now,
+		zones:         make(map[string]*zone),
+		tsigKeys:      make(map[string]*powerdns.TSIGKey),
+		autoprimaries: []powerdns.Autoprimary{},
+		views:         make(map[string][]string),
+		networks:      make(map[netip.Prefix]string),
+		statistics:    defaultStatistics(),
+		config:        []powerdns.ConfigSetting{},
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+// Client returns a client which is configured to use the server.
+func (s *Server) Client(options ...powerdns.NewOption) *powerdns.Client {
+	defaults := []powerdns.NewOption{powerdns.WithAPIKey(s.APIKey), powerdns.WithHTTPClient(s.Server.Client())}
+	return powerdns.New(s.URL, s.VHost, append(defaults, options...)...)
+}
+
+// AddZone creates a zone as if it had been posted to the API.
+func (s *Server) AddZone(zone powerdns.Zone) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.createZone(zone); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Zone returns a copy of a zone including its RRsets.
+func (s *Server) Zone(name string) (powerdns.Zone, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	z, ok := s.zones[canonicalName(name)]
+	if !ok {
+		return powerdns.Zone{}, false
+	}
+
+	var view powerdns.Zone
+	data, _ := json.Marshal(s.zoneView(z, true))
+	_ = json.Unmarshal(data, &view)
+	return view, true
+}
+
+// apiError is rendered as JSON error response
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func errorf(status int, format string, args ...any) *apiError {
+	return &apiError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// handlerFunc handles a request and returns the status code and the body, which is encoded as JSON unless it is a string.
+type handlerFunc func(r *http.Request) (int, any, *apiError)
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	handle := func(pattern string, h handlerFunc) {
+		mux.HandleFunc(pattern, s.wrap(h))
+	}
+
+	handle("GET /api/v1/servers", s.listServers)
+	handle("GET /api/v1/servers/{server}", s.getServer)
+	handle("PUT /api/v1/servers/{server}/cache/flush", s.flushCache)
+	handle("GET /api/v1/servers/{server}/config", s.listConfig)
+	handle("GET /api/v1/servers/{server}/statistics", s.listStatistics)
+	handle("GET /api/v1/servers/{server}/search-data", s.searchData)
+
+	handle("GET /api/v1/servers/{server}/zones", s.listZones)
+	handle("POST /api/v1/servers/{server}/zones", s.postZone)
+	handle("GET /api/v1/servers/{server}/zones/{zone}", s.getZone)
+	handle("PUT /api/v1/servers/{server}/zones/{zone}", s.putZone)
+	handle("PATCH /api/v1/servers/{server}/zones/{zone}", s.patchZone)
+	handle("DELETE /api/v1/servers/{server}/zones/{zone}", s.deleteZone)
+	handle("PUT /api/v1/servers/{server}/zones/{zone}/notify", s.notifyZone)
+	handle("PUT /api/v1/servers/{server}/zones/{zone}/axfr-retrieve", s.axfrRetrieveZone)
+	handle("PUT /api/v1/servers/{server}/zones/{zone}/rectify", s.rectifyZone)
+	handle("GET /api/v1/servers/{server}/zones/{zone}/export", s.exportZone)
+
+	handle("GET /api/v1/servers/{server}/zones/{zone}/metadata", s.listMetadata)
+	handle("POST /api/v1/servers/{server}/zones/{zone}/metadata", s.postMetadata)
+	handle("GET /api/v1/servers/{server}/zones/{zone}/metadata/{kind}", s.getMetadata)
+	handle("PUT /api/v1/servers/{server}/zones/{zone}/metadata/{kind}", s.putMetadata)
+	handle("DELETE /api/v1/servers/{server}/zones/{zone}/metadata/{kind}", s.deleteMetadata)
+
+	handle("GET /api/v1/servers/{server}/zones/{zone}/cryptokeys", s.listCryptokeys)
+	handle("POST /api/v1/servers/{server}/zones/{zone}/cryptokeys", s.postCryptokey)
+	handle("GET /api/v1/servers/{server}/zones/{zone}/cryptokeys/{id}", s.getCryptokey)
+	handle("PUT /api/v1/servers/{server}/zones/{zone}/cryptokeys/{id}", s.putCryptokey)
+	handle("DELETE /api/v1/servers/{server}/zones/{zone}/cryptokeys/{id}", s.deleteCryptokey)
+
+	handle("GET /api/v1/servers/{server}/tsigkeys", s.listTSIGKeys)
+	handle("POST /api/v1/servers/{server}/tsigkeys", s.postTSIGKey)
+	handle("GET /api/v1/servers/{server}/tsigkeys/{id}", s.getTSIGKey)
+	handle("PUT /api/v1/servers/{server}/tsigkeys/{id}", s.putTSIGKey)
+	handle("DELETE /api/v1/servers/{server}/tsigkeys/{id}", s.deleteTSIGKey)
+
+	handle("GET /api/v1/servers/{server}/autoprimaries", s.listAutoprimaries)
+	handle("POST /api/v1/servers/{server}/autoprimaries", s.postAutoprimary)
+	handle("DELETE /api/v1/servers/{server}/autoprimaries/{ip}/{nameserver}", s.deleteAutoprimary)
+
+	handle("GET /api/v1/servers/{server}/views", s.listViews)
+	handle("GET /api/v1/servers/{server}/views/{view}", s.getView)
+	handle("POST /api/v1/servers/{server}/views/{view}", s.postViewZone)
+	handle("DELETE /api/v1/servers/{server}/views/{view}/{zone}", s.deleteViewZone)
+
+	handle("GET /api/v1/servers/{server}/networks", s.listNetworks)
+	handle("GET /api/v1/servers/{server}/networks/{ip}/{prefixlen}", s.getNetwork)
+	handle("PUT /api/v1/servers/{server}/networks/{ip}/{prefixlen}", s.putNetwork)
+
+	return mux
+}
+
+// wrap authenticates the request, serialises access to the state and renders the response.
+func (s *Server) wrap(h handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != s.APIKey {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		status, body, err := s.serve(h, r)
+		if err != nil {
+			status, body = err.status, map[string]string{"error": err.message}
+		}
+
+		switch body := body.(type) {
+		case nil:
+			w.WriteHeader(status)
+		case string:
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(body)
+		}
+	}
+}
+
+func (s *Server) serve(h handlerFunc, r *http.Request) (int, any, *apiError) {
+	if server := r.PathValue("server"); server != "" && server != s.VHost {
+		return 0, nil, errorf(http.StatusNotFound, "Not Found")
+	}
+	return h(r)
+}
+
+// decodeBody decodes the JSON request body into v.
+func decodeBody(r *http.Request, v any) *apiError {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "Request body is not valid JSON: %s", err)
+	}
+	return nil
+}
+
+// canonicalName returns name in lower case with a trailing dot, followed by the variant if name is the ID of a zone variant.
+func canonicalName(name string) string {
+	zone, variant := powerdns.SplitZoneVariant(name)
+	return powerdns.ZoneVariant(strings.ToLower(zone), variant)
+}
+
+// inZone reports whether name equals or is below the zone.
+func inZone(name, zone string) bool {
+	return zone == "." || name == zone || strings.HasSuffix(name, "."+zone)
+}
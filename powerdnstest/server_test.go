@@ -0,0 +1,132 @@
+package powerdnstest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+var testTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func newTestServer(t *testing.T, options ...Option) (*Server, *powerdns.Client) {
+	t.Helper()
+
+	s := NewServer(append([]Option{WithClock(func() time.Time { return testTime })}, options...)...)
+	t.Cleanup(s.Close)
+	return s, s.Client(powerdns.WithRetryPolicy(powerdns.RetryPolicy{}))
+}
+
+func addTestZone(t *testing.T, s *Server, name string) {
+	t.Helper()
+
+	if err := s.AddZone(powerdns.Zone{Name: powerdns.String(name), Kind: powerdns.ZoneKindPtr(powerdns.NativeZoneKind), Nameservers: []string{"ns1.example.com", "ns2.example.com."}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// rawRequest sends a request to a path below the server resource and returns the status code and the body.
+func rawRequest(t *testing.T, s *Server, method, path, body string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, s.URL+"/api/v1/servers/"+s.VHost+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req.Header.Set("X-API-Key", s.APIKey)
+
+	resp, err := s.Server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestNewServerOptions(t *testing.T) {
+	s, client := newTestServer(t, WithAPIKey("secret"), WithVHost("vhost"))
+	if s.APIKey != "secret" || s.VHost != "vhost" {
+		t.Errorf("unexpected server %s %s", s.APIKey, s.VHost)
+	}
+
+	if _, err := client.Servers.Get(context.Background(), "vhost"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	s, _ := newTestServer(t)
+	client := powerdns.New(s.URL, s.VHost, powerdns.WithAPIKey("wrong"))
+
+	if _, err := client.Zones.List(context.Background()); !errors.Is(err, powerdns.ErrUnauthorized) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUnknownServer(t *testing.T) {
+	_, client := newTestServer(t)
+
+	if _, err := client.Servers.Get(context.Background(), "unknown"); !errors.Is(err, powerdns.ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestInvalidBody(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	if status, body := rawRequest(t, s, http.MethodPost, "/zones", "{"); status != http.StatusBadRequest || !strings.Contains(body, "not valid JSON") {
+		t.Errorf("unexpected response %d %s", status, body)
+	}
+}
+
+func TestAddZone(t *testing.T) {
+	s, client := newTestServer(t)
+	addTestZone(t, s, "example.org")
+	addTestZone(t, s, "example.com")
+
+	if err := s.AddZone(powerdns.Zone{Name: powerdns.String("Example.com.")}); err == nil || err.Error() != "Domain 'example.com.' already exists" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	zones, err := client.Zones.List(context.Background())
+	if err != nil || len(zones) != 2 || *zones[0].Name != "example.com." {
+		t.Errorf("unexpected zones %+v: %v", zones, err)
+	}
+
+	zone, ok := s.Zone("EXAMPLE.com")
+	if !ok || *zone.Name != "example.com." || len(zone.RRsets) != 2 {
+		t.Errorf("unexpected zone %+v", zone)
+	}
+
+	if _, ok := s.Zone("example.net"); ok {
+		t.Error("unexpected zone")
+	}
+}
+
+func TestCanonicalName(t *testing.T) {
+	testCases := []struct {
+		name, zone string
+		want       bool
+	}{
+		{"example.com.", "example.com.", true},
+		{"www.example.com.", "example.com.", true},
+		{"wwwexample.com.", "example.com.", false},
+		{"example.org.", "example.com.", false},
+		{"example.org.", ".", true},
+	}
+
+	for _, tc := range testCases {
+		if inZone(canonicalName(tc.name), tc.zone) != tc.want {
+			t.Errorf("inZone(%s, %s) != %t", tc.name, tc.zone, tc.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package powerdnstest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func (s *Server) serverView() powerdns.Server {
+	return powerdns.Server{
+		Type:       powerdns.String("Server"),
+		ID:         powerdns.String(s.VHost),
+		DaemonType: powerdns.String("authoritative"),
+		Version:    powerdns.String("powerdnstest"),
+		URL:        powerdns.String("/api/v1/servers/" + s.VHost),
+		ConfigURL:  powerdns.String("/api/v1/servers/" + s.VHost + "/config{/config_setting}"),
+		ZonesURL:   powerdns.String("/api/v1/servers/" + s.VHost + "/zones{/zone}"),
+	}
+}
+
+func (s *Server) listServers(*http.Request) (int, any, *apiError) {
+	return http.StatusOK, []powerdns.Server{s.serverView()}, nil
+}
+
+func (s *Server) getServer(*http.Request) (int, any, *apiError) {
+	return http.StatusOK, s.serverView(), nil
+}
+
+func (s *Server) flushCache(*http.Request) (int, any, *apiError) {
+	return http.StatusOK, powerdns.CacheFlushResult{Count: powerdns.Uint32(0), Result: powerdns.String("Flushed cache.")}, nil
+}
+
+func (s *Server) listConfig(*http.Request) (int, any, *apiError) {
+	return http.StatusOK, s.config, nil
+}
+
+func (s *Server) listStatistics(r *http.Request) (int, any, *apiError) {
+	query := r.URL.Query()
+	name := query.Get("statistic")
+	includeRings := query.Get("includerings") != "false"
+
+	statistics := make([]powerdns.Statistic, 0, len(s.statistics))
+	for _, statistic := range s.statistics {
+		if name != "" && powerdns.StringValue(statistic.Name) != name {
+			continue
+		}
+		if !includeRings && name == "" && powerdns.StringValue(statistic.Type) == "RingStatisticItem" {
+			continue
+		}
+		statistics = append(statistics, statistic)
+	}
+
+	if name != "" && len(statistics) == 0 {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "Unknown statistic name")
+	}
+
+	return http.StatusOK, statistics, nil
+}
+
+func defaultStatistics() []powerdns.Statistic {
+	simple := func(name, value string) powerdns.Statistic {
+		return powerdns.Statistic{Name: powerdns.String(name), Type: powerdns.String("StatisticItem"), Value: value}
+	}
+	entries := func(pairs ...string) []any {
+		values := make([]any, 0, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			values = append(values, map[string]any{"name": pairs[i], "value": pairs[i+1]})
+		}
+		return values
+	}
+
+	statistics := make([]powerdns.Statistic, 0)
+	for _, name := range strings.Fields("corrupt-packets deferred-cache-inserts packetcache-hit packetcache-miss query-cache-hit query-cache-miss servfail-packets tcp-queries udp-queries udp-answers") {
+		statistics = append(statistics, simple(name, "0"))
+	}
+	statistics = append(statistics,
+		simple("uptime", "1"),
+		powerdns.Statistic{Name: powerdns.String("response-by-qtype"), Type: powerdns.String("MapStatisticItem"), Value: entries("A", "0", "AAAA", "0")},
+		powerdns.Statistic{Name: powerdns.String("queries"), Type: powerdns.String("RingStatisticItem"), Size: powerdns.String("10000"), Value: entries("example.com/A", "0")},
+	)
+	return statistics
+}
@@ -0,0 +1,109 @@
+package powerdnstest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func TestServers(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	servers, err := client.Servers.List(ctx)
+	if err != nil || len(servers) != 1 || *servers[0].ID != DefaultVHost {
+		t.Errorf("unexpected servers %+v: %v", servers, err)
+	}
+
+	server, err := client.Servers.Get(ctx, DefaultVHost)
+	if err != nil || *server.DaemonType != "authoritative" {
+		t.Errorf("unexpected server %+v: %v", server, err)
+	}
+
+	result, err := client.Servers.CacheFlush(ctx, DefaultVHost, "example.com")
+	if err != nil || *result.Result != "Flushed cache." {
+		t.Errorf("unexpected result %+v: %v", result, err)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	_, client := newTestServer(t, WithConfig(powerdns.ConfigSetting{Name: powerdns.String("api"), Type: powerdns.String("ConfigSetting"), Value: powerdns.String("yes")}))
+
+	settings, err := client.Config.List(context.Background())
+	if err != nil || len(settings) != 1 || *settings[0].Value != "yes" {
+		t.Errorf("unexpected settings %+v: %v", settings, err)
+	}
+}
+
+func TestConfigDiff(t *testing.T) {
+	setting := func(name, value string) powerdns.ConfigSetting {
+		return powerdns.ConfigSetting{Name: powerdns.String(name), Type: powerdns.String("ConfigSetting"), Value: powerdns.String(value)}
+	}
+	_, primary := newTestServer(t, WithConfig(setting("api", "yes"), setting("default-ttl", "3600"), setting("server-id", "ns1")))
+	_, secondary := newTestServer(t, WithConfig(setting("api", "yes"), setting("default-ttl", "300"), setting("server-id", "ns2")))
+
+	differences, err := primary.Config.Diff(context.Background(), secondary, "server-id")
+	if err != nil || len(differences) != 1 || differences[0].Name != "default-ttl" || *differences[0].Value != "3600" || *differences[0].OtherValue != "300" {
+		t.Errorf("unexpected differences %+v: %v", differences, err)
+	}
+}
+
+func TestStatistics(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+
+	statistics, err := client.Statistics.List(ctx)
+	if err != nil || len(statistics) != len(defaultStatistics()) {
+		t.Errorf("unexpected statistics %+v: %v", statistics, err)
+	}
+
+	statistics, err = client.Statistics.Get(ctx, "queries")
+	if err != nil || len(statistics) != 1 || *statistics[0].Type != "RingStatisticItem" {
+		t.Errorf("unexpected statistics %+v: %v", statistics, err)
+	}
+
+	if _, err := client.Statistics.Get(ctx, "unknown"); !errors.Is(err, powerdns.ErrValidation) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if status, body := rawRequest(t, s, "GET", "/statistics?includerings=false", ""); status != 200 || len(body) == 0 || strings.Contains(body, "RingStatisticItem") {
+		t.Errorf("unexpected response %d %s", status, body)
+	}
+}
+
+func TestTypedStatistics(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	statistics, err := client.Statistics.ListTyped(ctx)
+	if err != nil || len(statistics) != len(defaultStatistics()) {
+		t.Errorf("unexpected statistics %+v: %v", statistics, err)
+	}
+	if ring, ok := statistics[len(statistics)-1].(powerdns.RingStatisticItem); !ok || ring.Size != 10000 || ring.Entries[0].Name != "example.com/A" {
+		t.Errorf("unexpected ring statistic %+v", statistics[len(statistics)-1])
+	}
+
+	statistics, err = client.Statistics.ListTyped(ctx, powerdns.WithoutRings())
+	if err != nil || len(statistics) != len(defaultStatistics())-1 {
+		t.Errorf("unexpected statistics %+v: %v", statistics, err)
+	}
+
+	if uptime, err := client.Statistics.Counter(ctx, "uptime"); err != nil || uptime != 1 {
+		t.Errorf("unexpected uptime %d: %v", uptime, err)
+	}
+	if _, err := client.Statistics.Counter(ctx, "response-by-qtype"); !errors.Is(err, powerdns.ErrStatisticType) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWithStatistics(t *testing.T) {
+	_, client := newTestServer(t, WithStatistics(powerdns.Statistic{Name: powerdns.String("uptime"), Type: powerdns.String("StatisticItem"), Value: "42"}))
+
+	statistics, err := client.Statistics.List(context.Background())
+	if err != nil || len(statistics) != 1 || statistics[0].Value != "42" {
+		t.Errorf("unexpected statistics %+v: %v", statistics, err)
+	}
+}
@@ -0,0 +1,117 @@
+package powerdnstest
+
+import (
+	"cmp"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"slices"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func (s *Server) lookupTSIGKey(r *http.Request) (*powerdns.TSIGKey, *apiError) {
+	key, ok := s.tsigKeys[canonicalName(r.PathValue("id"))]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "TSIG key with name '%s' not found", r.PathValue("id"))
+	}
+	return key, nil
+}
+
+// storeTSIGKey stores key under an ID derived from its name, unless another key with the same name exists.
+func (s *Server) storeTSIGKey(key *powerdns.TSIGKey) *apiError {
+	id := canonicalName(*key.Name)
+	if _, exists := s.tsigKeys[id]; exists {
+		return errorf(http.StatusConflict, "A TSIG key with the name '%s' already exists", *key.Name)
+	}
+
+	key.ID = powerdns.String(id)
+	s.tsigKeys[id] = key
+	return nil
+}
+
+func (s *Server) listTSIGKeys(*http.Request) (int, any, *apiError) {
+	keys := make([]powerdns.TSIGKey, 0, len(s.tsigKeys))
+	for _, key := range s.tsigKeys {
+		view := *key
+		view.Key = powerdns.String("")
+		keys = append(keys, view)
+	}
+	slices.SortFunc(keys, func(a, b powerdns.TSIGKey) int {
+		return cmp.Compare(*a.ID, *b.ID)
+	})
+
+	return http.StatusOK, keys, nil
+}
+
+func (s *Server) postTSIGKey(r *http.Request) (int, any, *apiError) {
+	var in powerdns.TSIGKey
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+	if in.Name == nil {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "Key 'name' not present or not a String")
+	}
+
+	key := &powerdns.TSIGKey{
+		Name:      in.Name,
+		Algorithm: powerdns.String(cmp.Or(powerdns.StringValue(in.Algorithm), "hmac-md5")),
+		Key:       in.Key,
+		Type:      powerdns.String("TSIGKey"),
+	}
+	if powerdns.StringValue(key.Key) == "" {
+		secret := make([]byte, 64)
+		_, _ = rand.Read(secret)
+		key.Key = powerdns.String(base64.StdEncoding.EncodeToString(secret))
+	}
+
+	if err := s.storeTSIGKey(key); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusCreated, key, nil
+}
+
+func (s *Server) getTSIGKey(r *http.Request) (int, any, *apiError) {
+	key, err := s.lookupTSIGKey(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, key, nil
+}
+
+func (s *Server) putTSIGKey(r *http.Request) (int, any, *apiError) {
+	key, err := s.lookupTSIGKey(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var in powerdns.TSIGKey
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+
+	changed := *key
+	changed.Algorithm = cmp.Or(in.Algorithm, key.Algorithm)
+	changed.Key = cmp.Or(in.Key, key.Key)
+	if in.Name != nil && canonicalName(*in.Name) != *key.ID {
+		changed.Name = in.Name
+		if err := s.storeTSIGKey(&changed); err != nil {
+			return 0, nil, err
+		}
+		delete(s.tsigKeys, *key.ID)
+		return http.StatusOK, &changed, nil
+	}
+
+	*key = changed
+	return http.StatusOK, key, nil
+}
+
+func (s *Server) deleteTSIGKey(r *http.Request) (int, any, *apiError) {
+	key, err := s.lookupTSIGKey(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	delete(s.tsigKeys, *key.ID)
+	return http.StatusNoContent, nil, nil
+}
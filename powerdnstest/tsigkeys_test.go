@@ -0,0 +1,84 @@
+package powerdnstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func isConflict(err error) bool {
+	var apiErr *powerdns.Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+func TestTSIGKeys(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	generated, err := client.TSIGKeys.Create(ctx, "generated", "", "")
+	if err != nil || *generated.ID != "generated." || *generated.Algorithm != "hmac-md5" || *generated.Key == "" {
+		t.Errorf("unexpected key %+v: %v", generated, err)
+	}
+	provided, err := client.TSIGKeys.Create(ctx, "provided", "hmac-sha256", "c2VjcmV0")
+	if err != nil || *provided.Key != "c2VjcmV0" {
+		t.Errorf("unexpected key %+v: %v", provided, err)
+	}
+	if _, err := client.TSIGKeys.Create(ctx, "Provided.", "hmac-sha256", ""); !isConflict(err) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	keys, err := client.TSIGKeys.List(ctx)
+	if err != nil || len(keys) != 2 || *keys[0].ID != "generated." || *keys[1].Key != "" {
+		t.Errorf("unexpected keys %+v: %v", keys, err)
+	}
+
+	changed, err := client.TSIGKeys.Change(ctx, "provided.", powerdns.TSIGKey{Algorithm: powerdns.String("hmac-sha512")})
+	if err != nil || *changed.Algorithm != "hmac-sha512" || *changed.Key != "c2VjcmV0" {
+		t.Errorf("unexpected key %+v: %v", changed, err)
+	}
+	if _, err := client.TSIGKeys.Change(ctx, "provided.", powerdns.TSIGKey{Name: powerdns.String("generated")}); !isConflict(err) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	renamed, err := client.TSIGKeys.Change(ctx, "provided.", powerdns.TSIGKey{Name: powerdns.String("renamed")})
+	if err != nil || *renamed.ID != "renamed." {
+		t.Errorf("unexpected key %+v: %v", renamed, err)
+	}
+
+	key, err := client.TSIGKeys.Get(ctx, "renamed.")
+	if err != nil || *key.Algorithm != "hmac-sha512" {
+		t.Errorf("unexpected key %+v: %v", key, err)
+	}
+	if _, err := client.TSIGKeys.Get(ctx, "provided."); !errors.Is(err, powerdns.ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := client.TSIGKeys.Delete(ctx, "renamed."); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := client.TSIGKeys.Delete(ctx, "renamed."); !errors.Is(err, powerdns.ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTSIGKeyErrors(t *testing.T) {
+	s, client := newTestServer(t)
+	if _, err := client.TSIGKeys.Create(context.Background(), "key", "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if status, _ := rawRequest(t, s, http.MethodPost, "/tsigkeys", "{"); status != http.StatusBadRequest {
+		t.Errorf("unexpected status %d", status)
+	}
+	if status, _ := rawRequest(t, s, http.MethodPost, "/tsigkeys", "{}"); status != http.StatusUnprocessableEntity {
+		t.Errorf("unexpected status %d", status)
+	}
+	if status, _ := rawRequest(t, s, http.MethodPut, "/tsigkeys/key.", "{"); status != http.StatusBadRequest {
+		t.Errorf("unexpected status %d", status)
+	}
+	if status, _ := rawRequest(t, s, http.MethodPut, "/tsigkeys/unknown.", "{}"); status != http.StatusNotFound {
+		t.Errorf("unexpected status %d", status)
+	}
+}
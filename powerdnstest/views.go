@@ -0,0 +1,119 @@
+package powerdnstest
+
+import (
+	"cmp"
+	"net/http"
+	"net/netip"
+	"slices"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func (s *Server) listViews(*http.Request) (int, any, *apiError) {
+	views := powerdns.Views{Views: make([]string, 0, len(s.views))}
+	for view := range s.views {
+		views.Views = append(views.Views, view)
+	}
+	slices.Sort(views.Views)
+
+	return http.StatusOK, views, nil
+}
+
+func (s *Server) getView(r *http.Request) (int, any, *apiError) {
+	zones, ok := s.views[r.PathValue("view")]
+	if !ok {
+		return 0, nil, errorf(http.StatusNotFound, "View '%s' not found", r.PathValue("view"))
+	}
+	return http.StatusOK, powerdns.View{Zones: zones}, nil
+}
+
+func (s *Server) postViewZone(r *http.Request) (int, any, *apiError) {
+	var in struct {
+		Name *string `json:"name"`
+	}
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+	if in.Name == nil {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "Key 'name' not present or not a String")
+	}
+	name := canonicalName(*in.Name)
+	if _, ok := s.zones[name]; !ok {
+		return 0, nil, errorf(http.StatusNotFound, "Could not find domain '%s'", name)
+	}
+
+	view := r.PathValue("view")
+	if i, found := slices.BinarySearch(s.views[view], name); !found {
+		s.views[view] = slices.Insert(s.views[view], i, name)
+	}
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) deleteViewZone(r *http.Request) (int, any, *apiError) {
+	view := r.PathValue("view")
+	i, found := slices.BinarySearch(s.views[view], canonicalName(r.PathValue("zone")))
+	if !found {
+		return 0, nil, errorf(http.StatusNotFound, "Zone '%s' is not in view '%s'", r.PathValue("zone"), view)
+	}
+
+	s.views[view] = slices.Delete(s.views[view], i, i+1)
+	if len(s.views[view]) == 0 {
+		delete(s.views, view)
+	}
+	return http.StatusNoContent, nil, nil
+}
+
+// networkPrefix parses the prefix from the path of a network
+func networkPrefix(r *http.Request) (netip.Prefix, *apiError) {
+	prefix, err := netip.ParsePrefix(r.PathValue("ip") + "/" + r.PathValue("prefixlen"))
+	if err != nil {
+		return netip.Prefix{}, errorf(http.StatusUnprocessableEntity, "Invalid network: %s", err)
+	}
+	return prefix.Masked(), nil
+}
+
+func (s *Server) listNetworks(*http.Request) (int, any, *apiError) {
+	networks := powerdns.Networks{Networks: make([]powerdns.Network, 0, len(s.networks))}
+	for prefix, view := range s.networks {
+		networks.Networks = append(networks.Networks, powerdns.Network{Network: &prefix, View: powerdns.String(view)})
+	}
+	slices.SortFunc(networks.Networks, func(a, b powerdns.Network) int {
+		return cmp.Or(a.Network.Addr().Compare(b.Network.Addr()), cmp.Compare(a.Network.Bits(), b.Network.Bits()))
+	})
+
+	return http.StatusOK, networks, nil
+}
+
+func (s *Server) getNetwork(r *http.Request) (int, any, *apiError) {
+	prefix, err := networkPrefix(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	view, ok := s.networks[prefix]
+	if !ok {
+		return 0, nil, errorf(http.StatusNotFound, "Network '%s' not found", prefix)
+	}
+	return http.StatusOK, powerdns.Network{Network: &prefix, View: powerdns.String(view)}, nil
+}
+
+func (s *Server) putNetwork(r *http.Request) (int, any, *apiError) {
+	prefix, err := networkPrefix(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var in powerdns.Network
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+	if in.View == nil {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "Key 'view' not present or not a String")
+	}
+
+	if *in.View == "" {
+		delete(s.networks, prefix)
+	} else {
+		s.networks[prefix] = *in.View
+	}
+	return http.StatusNoContent, nil, nil
+}
@@ -0,0 +1,120 @@
+package powerdnstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/netip"
+	"slices"
+	"testing"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func TestZoneVariants(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+	addTestZone(t, s, powerdns.ZoneVariant("Example.com", "internal"))
+
+	zone, err := client.Zones.Get(ctx, "example.com..internal")
+	if err != nil || *zone.ID != "example.com..internal" || *zone.RRsets[0].Name != "example.com." {
+		t.Errorf("unexpected zone %+v: %v", zone, err)
+	}
+
+	if err := client.Records.Add(ctx, "example.com..internal", "www.example.com", powerdns.RRTypeA, 60, []string{"192.0.2.1"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	rrSets, err := client.Records.Get(ctx, "example.com..internal", "www.example.com", nil)
+	if err != nil || len(rrSets) != 1 {
+		t.Errorf("unexpected RRsets %+v: %v", rrSets, err)
+	}
+	if rrSets, err := client.Records.Get(ctx, "example.com", "www.example.com", nil); err != nil || len(rrSets) != 0 {
+		t.Errorf("unexpected RRsets %+v: %v", rrSets, err)
+	}
+}
+
+func TestViews(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+	addTestZone(t, s, "example.com..internal")
+
+	for _, member := range []struct{ view, zone string }{{"internal", "example.com..internal"}, {"internal", "example.com..internal"}, {"external", "example.com"}} {
+		if err := client.Views.AddZone(ctx, member.view, member.zone); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if err := client.Views.AddZone(ctx, "internal", "example.org"); !errors.Is(err, powerdns.ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	views, err := client.Views.List(ctx)
+	if err != nil || !slices.Equal(views, []string{"external", "internal"}) {
+		t.Errorf("unexpected views %v: %v", views, err)
+	}
+	zones, err := client.Views.Get(ctx, "internal")
+	if err != nil || !slices.Equal(zones, []string{"example.com..internal"}) {
+		t.Errorf("unexpected zones %v: %v", zones, err)
+	}
+
+	if err := client.Views.RemoveZone(ctx, "external", "example.com"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := client.Views.RemoveZone(ctx, "external", "example.com"); !errors.Is(err, powerdns.ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := client.Views.Get(ctx, "external"); !errors.Is(err, powerdns.ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNetworks(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	for _, network := range []struct{ prefix, view string }{{"2001:db8::/32", "internal"}, {"192.0.2.0/24", "internal"}, {"192.0.2.1/16", "external"}} {
+		if err := client.Networks.SetView(ctx, netip.MustParsePrefix(network.prefix), network.view); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	networks, err := client.Networks.List(ctx)
+	if err != nil || len(networks) != 3 || networks[0].Network.String() != "192.0.0.0/16" || networks[1].Network.String() != "192.0.2.0/24" || *networks[2].View != "internal" {
+		t.Errorf("unexpected networks %+v: %v", networks, err)
+	}
+
+	network, err := client.Networks.Get(ctx, netip.MustParsePrefix("192.0.2.0/24"))
+	if err != nil || *network.View != "internal" {
+		t.Errorf("unexpected network %+v: %v", network, err)
+	}
+
+	if err := client.Networks.SetView(ctx, netip.MustParsePrefix("192.0.2.0/24"), ""); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := client.Networks.Get(ctx, netip.MustParsePrefix("192.0.2.0/24")); !errors.Is(err, powerdns.ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestViewAndNetworkErrors(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	testCases := []struct {
+		method, path, body string
+		wantStatus         int
+	}{
+		{http.MethodPost, "/views/internal", "{", http.StatusBadRequest},
+		{http.MethodPost, "/views/internal", "{}", http.StatusUnprocessableEntity},
+		{http.MethodGet, "/networks/invalid/24", "", http.StatusUnprocessableEntity},
+		{http.MethodPut, "/networks/invalid/24", "{}", http.StatusUnprocessableEntity},
+		{http.MethodPut, "/networks/192.0.2.0/24", "{", http.StatusBadRequest},
+		{http.MethodPut, "/networks/192.0.2.0/24", "{}", http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		if status, _ := rawRequest(t, s, tc.method, tc.path, tc.body); status != tc.wantStatus {
+			t.Errorf("%s %s: unexpected status %d", tc.method, tc.path, status)
+		}
+	}
+}
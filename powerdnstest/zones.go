@@ -0,0 +1,373 @@
+package powerdnstest
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+// zone is the in-memory state of a zone
+type zone struct {
+	attributes powerdns.Zone
+	rrSets     []powerdns.RRset
+	metadata   map[powerdns.MetadataKind][]string
+	cryptokeys []*powerdns.Cryptokey
+}
+
+func (z *zone) name() string {
+	return *z.attributes.Name
+}
+
+// apex returns the name of the zone without its variant, which is the owner of the SOA RRset
+func (z *zone) apex() string {
+	apex, _ := powerdns.SplitZoneVariant(z.name())
+	return apex
+}
+
+func (z *zone) findRRset(name string, recordType powerdns.RRType) *powerdns.RRset {
+	for i := range z.rrSets {
+		if *z.rrSets[i].Name == name && *z.rrSets[i].Type == recordType {
+			return &z.rrSets[i]
+		}
+	}
+	return nil
+}
+
+// soaFields returns the fields of the SOA record, or nil if there is no valid SOA record.
+func (z *zone) soaFields() []string {
+	soa := z.findRRset(z.apex(), powerdns.RRTypeSOA)
+	if soa == nil || len(soa.Records) == 0 {
+		return nil
+	}
+
+	fields := strings.Fields(powerdns.StringValue(soa.Records[0].Content))
+	if len(fields) != 7 {
+		return nil
+	}
+	return fields
+}
+
+func (z *zone) serial() uint32 {
+	fields := z.soaFields()
+	if fields == nil {
+		return 0
+	}
+
+	serial, _ := strconv.ParseUint(fields[2], 10, 32)
+	return uint32(serial)
+}
+
+func (z *zone) metadataValue(kind powerdns.MetadataKind) string {
+	if values := z.metadata[kind]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (z *zone) setMetadataValue(kind powerdns.MetadataKind, value *string) {
+	switch {
+	case value == nil:
+	case *value == "":
+		delete(z.metadata, kind)
+	default:
+		z.metadata[kind] = []string{*value}
+	}
+}
+
+func (z *zone) dnssec() bool {
+	if powerdns.BoolValue(z.attributes.Presigned) {
+		return true
+	}
+	return slices.ContainsFunc(z.cryptokeys, func(key *powerdns.Cryptokey) bool {
+		return powerdns.BoolValue(key.Active)
+	})
+}
+
+func (s *Server) zoneView(z *zone, includeRRsets bool) powerdns.Zone {
+	view := z.attributes
+	view.ID = powerdns.String(z.name())
+	view.URL = powerdns.String("/api/v1/servers/" + s.VHost + "/zones/" + z.name())
+	view.Type = powerdns.ZoneTypePtr(powerdns.ZoneZoneType)
+	view.Serial = powerdns.Uint32(z.serial())
+	view.EditedSerial = powerdns.Uint32(z.serial())
+	view.NotifiedSerial = powerdns.Uint32(0)
+	view.DNSsec = powerdns.Bool(z.dnssec())
+	view.SOAEdit = powerdns.String(z.metadataValue(powerdns.MetadataSOAEdit))
+	view.SOAEditAPI = powerdns.String(z.metadataValue(powerdns.MetadataSOAEditAPI))
+	view.APIRectify = powerdns.Bool(z.metadataValue(powerdns.MetadataAPIRectify) == "1")
+
+	if includeRRsets {
+		view.RRsets = slices.Clone(z.rrSets)
+		slices.SortFunc(view.RRsets, compareRRsets)
+	}
+	return view
+}
+
+func compareRRsets(a, b powerdns.RRset) int {
+	return cmp.Or(cmp.Compare(*a.Name, *b.Name), cmp.Compare(*a.Type, *b.Type))
+}
+
+func (s *Server) lookupZone(r *http.Request) (*zone, *apiError) {
+	name := canonicalName(r.PathValue("zone"))
+	z, ok := s.zones[name]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "Could not find domain '%s'", name)
+	}
+	return z, nil
+}
+
+func (s *Server) createZone(in powerdns.Zone) (*zone, *apiError) {
+	if in.Name == nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "Key 'name' not present or not a String")
+	}
+	name := canonicalName(*in.Name)
+	if _, exists := s.zones[name]; exists {
+		return nil, errorf(http.StatusConflict, "Domain '%s' already exists", name)
+	}
+	if in.Kind == nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "Key 'kind' not present or not a String")
+	}
+
+	z := &zone{metadata: make(map[powerdns.MetadataKind][]string)}
+	z.attributes = powerdns.Zone{Name: powerdns.String(name)}
+	s.updateZone(z, in)
+	if in.SOAEditAPI == nil {
+		z.metadata[powerdns.MetadataSOAEditAPI] = []string{"DEFAULT"}
+	}
+
+	apex := z.apex()
+	rrSets := make([]powerdns.RRset, 0, len(in.RRsets)+2)
+	for _, rrSet := range in.RRsets {
+		if rrSet.Name == nil || rrSet.Type == nil {
+			return nil, errorf(http.StatusUnprocessableEntity, "RRset has no name or type")
+		}
+		rrSet.Name = powerdns.String(canonicalName(*rrSet.Name))
+		rrSet.ChangeType = nil
+		if err := validateRRset(apex, rrSet); err != nil {
+			return nil, err
+		}
+		rrSets = append(rrSets, rrSet)
+	}
+	z.rrSets = rrSets
+	if err := validateRRsets(z.rrSets); err != nil {
+		return nil, err
+	}
+
+	if z.findRRset(apex, powerdns.RRTypeSOA) == nil {
+		z.rrSets = append(z.rrSets, newRRset(apex, powerdns.RRTypeSOA, "a.misconfigured.dns.server.invalid. hostmaster."+apex+" 0 10800 3600 604800 3600"))
+	}
+	if z.findRRset(apex, powerdns.RRTypeNS) == nil && len(in.Nameservers) > 0 {
+		nameservers := newRRset(apex, powerdns.RRTypeNS)
+		for _, nameserver := range in.Nameservers {
+			nameservers.Records = append(nameservers.Records, powerdns.Record{Content: powerdns.String(canonicalName(nameserver)), Disabled: powerdns.Bool(false)})
+		}
+		z.rrSets = append(z.rrSets, nameservers)
+	}
+
+	s.bumpSerial(z)
+	s.zones[name] = z
+	s.updateDNSSEC(z, in.DNSsec)
+	return z, nil
+}
+
+func newRRset(name string, recordType powerdns.RRType, contents ...string) powerdns.RRset {
+	rrSet := powerdns.RRset{Name: powerdns.String(name), Type: &recordType, TTL: powerdns.Uint32(3600), Records: []powerdns.Record{}}
+	for _, content := range contents {
+		rrSet.Records = append(rrSet.Records, powerdns.Record{Content: powerdns.String(content), Disabled: powerdns.Bool(false)})
+	}
+	return rrSet
+}
+
+// updateZone applies the attributes which are present in in.
+func (s *Server) updateZone(z *zone, in powerdns.Zone) {
+	a := &z.attributes
+	a.Kind = cmp.Or(in.Kind, a.Kind)
+	a.Account = cmp.Or(in.Account, a.Account)
+	a.Catalog = cmp.Or(in.Catalog, a.Catalog)
+	a.Nsec3Param = cmp.Or(in.Nsec3Param, a.Nsec3Param)
+	a.Nsec3Narrow = cmp.Or(in.Nsec3Narrow, a.Nsec3Narrow)
+	a.Presigned = cmp.Or(in.Presigned, a.Presigned)
+	if in.Masters != nil {
+		a.Masters = in.Masters
+	}
+	if in.MasterTSIGKeyIDs != nil {
+		a.MasterTSIGKeyIDs = in.MasterTSIGKeyIDs
+	}
+	if in.SlaveTSIGKeyIDs != nil {
+		a.SlaveTSIGKeyIDs = in.SlaveTSIGKeyIDs
+	}
+
+	z.setMetadataValue(powerdns.MetadataSOAEdit, in.SOAEdit)
+	z.setMetadataValue(powerdns.MetadataSOAEditAPI, in.SOAEditAPI)
+	if in.APIRectify != nil {
+		rectify := ""
+		if *in.APIRectify {
+			rectify = "1"
+		}
+		z.setMetadataValue(powerdns.MetadataAPIRectify, &rectify)
+	}
+}
+
+func (s *Server) listZones(r *http.Request) (int, any, *apiError) {
+	query := r.URL.Query()
+	filter := query.Get("zone")
+
+	zones := make([]powerdns.Zone, 0, len(s.zones))
+	for name, z := range s.zones {
+		if filter != "" && name != canonicalName(filter) {
+			continue
+		}
+		view := s.zoneView(z, false)
+		if query.Get("dnssec") == "false" {
+			view.DNSsec = nil
+		}
+		zones = append(zones, view)
+	}
+	slices.SortFunc(zones, func(a, b powerdns.Zone) int {
+		return cmp.Compare(*a.Name, *b.Name)
+	})
+
+	return http.StatusOK, zones, nil
+}
+
+func (s *Server) postZone(r *http.Request) (int, any, *apiError) {
+	var in powerdns.Zone
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+
+	z, err := s.createZone(in)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusCreated, s.zoneView(z, true), nil
+}
+
+func (s *Server) getZone(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	query := r.URL.Query()
+	view := s.zoneView(z, query.Get("rrsets") != "false")
+
+	if name := query.Get("rrset_name"); name != "" {
+		recordType := powerdns.RRType(query.Get("rrset_type"))
+		view.RRsets = slices.DeleteFunc(view.RRsets, func(rrSet powerdns.RRset) bool {
+			return *rrSet.Name != canonicalName(name) || (recordType != "" && *rrSet.Type != recordType)
+		})
+	}
+
+	if query.Get("include_disabled") == "false" {
+		view.RRsets = enabledRRsets(view.RRsets)
+	}
+
+	return http.StatusOK, view, nil
+}
+
+// enabledRRsets returns copies of rrSets without their disabled records, omitting RRsets which have no records left
+func enabledRRsets(rrSets []powerdns.RRset) []powerdns.RRset {
+	enabled := make([]powerdns.RRset, 0, len(rrSets))
+	for _, rrSet := range rrSets {
+		rrSet.Records = slices.DeleteFunc(slices.Clone(rrSet.Records), func(record powerdns.Record) bool {
+			return powerdns.BoolValue(record.Disabled)
+		})
+		if len(rrSet.Records) > 0 {
+			enabled = append(enabled, rrSet)
+		}
+	}
+	return enabled
+}
+
+func (s *Server) putZone(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var in powerdns.Zone
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+
+	s.updateZone(z, in)
+	s.updateDNSSEC(z, in.DNSsec)
+	return http.StatusNoContent, nil, nil
+}
+
+// updateDNSSEC secures a zone with a default key or removes all keys.
+func (s *Server) updateDNSSEC(z *zone, dnssec *bool) {
+	switch {
+	case dnssec == nil:
+	case *dnssec && len(z.cryptokeys) == 0:
+		key, _ := s.generateCryptokey(z.apex(), powerdns.Cryptokey{KeyType: powerdns.String("csk"), Active: powerdns.Bool(true)})
+		z.cryptokeys = append(z.cryptokeys, key)
+	case !*dnssec:
+		z.cryptokeys = nil
+	}
+}
+
+func (s *Server) deleteZone(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	delete(s.zones, z.name())
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) notifyZone(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if isSecondary(z) {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "Domain '%s' is not a primary", z.name())
+	}
+	return http.StatusOK, powerdns.NotifyResult{Result: powerdns.String("Notification queued")}, nil
+}
+
+func (s *Server) axfrRetrieveZone(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if !isSecondary(z) {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "Domain '%s' is not a secondary domain (or does not exist)", z.name())
+	}
+	return http.StatusOK, powerdns.AxfrRetrieveResult{Result: powerdns.String("Added retrieval request for '" + z.name() + "' from primary " + strings.Join(z.attributes.Masters, ", "))}, nil
+}
+
+func (s *Server) rectifyZone(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if powerdns.BoolValue(z.attributes.Presigned) {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "Zone '%s' is pre-signed, not rectifying.", z.name())
+	}
+	return http.StatusOK, powerdns.RectifyResult{Result: powerdns.String("Rectified")}, nil
+}
+
+func isSecondary(z *zone) bool {
+	kind := *z.attributes.Kind
+	return kind == powerdns.SlaveZoneKind || kind == powerdns.ConsumerZoneKind
+}
+
+func (s *Server) exportZone(r *http.Request) (int, any, *apiError) {
+	z, err := s.lookupZone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, string(powerdns.NewExport(s.zoneView(z, true).RRsets)), nil
+}
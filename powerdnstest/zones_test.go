@@ -0,0 +1,267 @@
+package powerdnstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/joeig/go-powerdns/v3"
+)
+
+func TestZoneLifecycle(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	zone, err := client.Zones.AddNative(ctx, "example.com", true, "", false, "", "DEFAULT", true, []string{"ns1.example.com."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *zone.Serial != 2024010201 || !*zone.DNSsec || *zone.SOAEditAPI != "DEFAULT" || len(zone.RRsets) != 2 {
+		t.Errorf("unexpected zone %+v", zone)
+	}
+
+	if _, err := client.Zones.AddNative(ctx, "example.com", true, "", false, "", "", true, nil); !errors.Is(err, powerdns.ErrZoneAlreadyExists) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	zones, err := client.Zones.List(ctx)
+	if err != nil || len(zones) != 1 || zones[0].RRsets != nil {
+		t.Errorf("unexpected zones %+v: %v", zones, err)
+	}
+
+	if err := client.Zones.Change(ctx, "example.com", &powerdns.Zone{Account: powerdns.String("ops"), Masters: []string{}, MasterTSIGKeyIDs: []string{"a."}, SlaveTSIGKeyIDs: []string{"b."}, APIRectify: powerdns.Bool(true), SOAEdit: powerdns.String("INCEPTION-INCREMENT"), DNSsec: powerdns.Bool(false)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	zone, err = client.Zones.Get(ctx, "example.com")
+	if err != nil || *zone.Account != "ops" || !*zone.APIRectify || *zone.SOAEdit != "INCEPTION-INCREMENT" || *zone.DNSsec || zone.SlaveTSIGKeyIDs[0] != "b." {
+		t.Errorf("unexpected zone %+v: %v", zone, err)
+	}
+
+	if err := client.Zones.Change(ctx, "example.com", &powerdns.Zone{APIRectify: powerdns.Bool(false), SOAEdit: powerdns.String(""), DNSsec: powerdns.Bool(true)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	zone, _ = client.Zones.Get(ctx, "example.com")
+	if *zone.APIRectify || *zone.SOAEdit != "" || !*zone.DNSsec {
+		t.Errorf("unexpected zone %+v", zone)
+	}
+
+	if err := client.Zones.Delete(ctx, "example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Zones.Get(ctx, "example.com"); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestListZones(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+	addTestZone(t, s, "example.org")
+
+	zones, err := client.Zones.List(ctx, powerdns.WithZoneName("Example.org"), powerdns.WithoutDNSSEC())
+	if err != nil || len(zones) != 1 || *zones[0].Name != "example.org." || zones[0].DNSsec != nil {
+		t.Errorf("unexpected zones %+v: %v", zones, err)
+	}
+
+	var names []string
+	for zone, err := range client.Zones.All(ctx) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if zone.DNSsec == nil {
+			t.Errorf("unexpected zone %+v", zone)
+		}
+		names = append(names, *zone.Name)
+	}
+	if strings.Join(names, " ") != "example.com. example.org." {
+		t.Errorf("unexpected zones %v", names)
+	}
+}
+
+func TestCreateZoneErrors(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	testCases := []struct {
+		body       string
+		wantStatus int
+	}{
+		{`{}`, http.StatusUnprocessableEntity},
+		{`{"name": "example.com."}`, http.StatusUnprocessableEntity},
+		{`{"name": "example.com.", "kind": "Native", "rrsets": [{"name": "www.example.com."}]}`, http.StatusUnprocessableEntity},
+		{`{"name": "example.com.", "kind": "Native", "rrsets": [{"name": "www.example.org.", "type": "A"}]}`, http.StatusUnprocessableEntity},
+		{`{"name": "example.com.", "kind": "Native", "rrsets": [{"name": "www.example.com.", "type": "A", "ttl": 60, "records": [{"content": "192.0.2.1"}]}, {"name": "www.example.com.", "type": "CNAME", "ttl": 60, "records": [{"content": "example.com."}]}]}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		if status, body := rawRequest(t, s, http.MethodPost, "/zones", tc.body); status != tc.wantStatus {
+			t.Errorf("unexpected response %d %s", status, body)
+		}
+	}
+}
+
+func TestCreateZoneWithRRsets(t *testing.T) {
+	s, client := newTestServer(t)
+
+	zone := &powerdns.Zone{
+		Name:       powerdns.String("example.com"),
+		Kind:       powerdns.ZoneKindPtr(powerdns.MasterZoneKind),
+		SOAEditAPI: powerdns.String("OFF"),
+		Presigned:  powerdns.Bool(true),
+		RRsets: []powerdns.RRset{
+			{Name: powerdns.String("example.com."), Type: powerdns.RRTypePtr(powerdns.RRTypeSOA), TTL: powerdns.Uint32(3600), Records: []powerdns.Record{{Content: powerdns.String("ns1.example.com. hostmaster.example.com. 5 10800 3600 604800 3600")}}},
+			{Name: powerdns.String("WWW.example.com"), Type: powerdns.RRTypePtr(powerdns.RRTypeA), TTL: powerdns.Uint32(60), Records: []powerdns.Record{{Content: powerdns.String("192.0.2.1")}}},
+		},
+	}
+	created, err := client.Zones.Add(context.Background(), zone)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *created.Serial != 5 || !*created.DNSsec || len(created.RRsets) != 2 || *created.RRsets[1].Name != "www.example.com." {
+		t.Errorf("unexpected zone %+v", created)
+	}
+
+	if status, body := rawRequest(t, s, http.MethodGet, "/zones?zone=example.org", ""); status != http.StatusOK || body != "[]\n" {
+		t.Errorf("unexpected response %d %s", status, body)
+	}
+	if status, body := rawRequest(t, s, http.MethodGet, "/zones?zone=example.com", ""); status != http.StatusOK || !strings.Contains(body, "example.com.") {
+		t.Errorf("unexpected response %d %s", status, body)
+	}
+}
+
+func TestGetZoneFilters(t *testing.T) {
+	s, client := newTestServer(t)
+	addTestZone(t, s, "example.com")
+
+	if status, body := rawRequest(t, s, http.MethodGet, "/zones/example.com.?rrsets=false", ""); status != http.StatusOK || strings.Contains(body, "rrsets") {
+		t.Errorf("unexpected response %d %s", status, body)
+	}
+
+	rrSets, err := client.Records.Get(context.Background(), "example.com", "example.com", powerdns.RRTypePtr(powerdns.RRTypeNS))
+	if err != nil || len(rrSets) != 1 || len(rrSets[0].Records) != 2 {
+		t.Errorf("unexpected RRsets %+v: %v", rrSets, err)
+	}
+
+	rrSets, err = client.Records.Get(context.Background(), "example.com", "example.com", nil)
+	if err != nil || len(rrSets) != 2 {
+		t.Errorf("unexpected RRsets %+v: %v", rrSets, err)
+	}
+
+	zone, err := client.Zones.Get(context.Background(), "example.com", powerdns.WithoutRRsets())
+	if err != nil || *zone.Serial == 0 || zone.RRsets != nil {
+		t.Errorf("unexpected zone %+v: %v", zone, err)
+	}
+
+	patch := &powerdns.RRsets{Sets: []powerdns.RRset{
+		{Name: powerdns.String("a.example.com."), Type: powerdns.RRTypePtr(powerdns.RRTypeA), TTL: powerdns.Uint32(60), ChangeType: powerdns.ChangeTypePtr(powerdns.ChangeTypeReplace), Records: []powerdns.Record{
+			{Content: powerdns.String("192.0.2.1"), Disabled: powerdns.Bool(true)},
+			{Content: powerdns.String("192.0.2.2"), Disabled: powerdns.Bool(false)},
+		}},
+		{Name: powerdns.String("b.example.com."), Type: powerdns.RRTypePtr(powerdns.RRTypeA), TTL: powerdns.Uint32(60), ChangeType: powerdns.ChangeTypePtr(powerdns.ChangeTypeReplace), Records: []powerdns.Record{
+			{Content: powerdns.String("192.0.2.3"), Disabled: powerdns.Bool(true)},
+		}},
+	}}
+	if err := client.Records.Patch(context.Background(), "example.com", patch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	zone, err = client.Zones.Get(context.Background(), "example.com", powerdns.WithoutDisabledRecords())
+	if err != nil || len(zone.RRsets) != 3 || *zone.RRsets[0].Name != "a.example.com." || len(zone.RRsets[0].Records) != 1 {
+		t.Errorf("unexpected zone %+v: %v", zone, err)
+	}
+	if zone, _ := s.Zone("example.com"); len(zone.RRsets) != 4 || len(zone.RRsets[0].Records) != 2 {
+		t.Errorf("unexpected stored zone %+v", zone)
+	}
+}
+
+func TestZoneChangeErrors(t *testing.T) {
+	s, client := newTestServer(t)
+	addTestZone(t, s, "example.com")
+
+	if err := client.Zones.Change(context.Background(), "example.org", &powerdns.Zone{}); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if status, _ := rawRequest(t, s, http.MethodPut, "/zones/example.com.", "{"); status != http.StatusBadRequest {
+		t.Errorf("unexpected status %d", status)
+	}
+	if err := client.Zones.Delete(context.Background(), "example.org"); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if status, _ := rawRequest(t, s, http.MethodPost, "/zones", `{"name": "example.com.", "kind": "Native"}`); status != http.StatusConflict {
+		t.Errorf("unexpected status %d", status)
+	}
+}
+
+func TestNotifyAndAxfrRetrieve(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+	if _, err := client.Zones.AddSlave(ctx, "example.org", []string{"192.0.2.1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result, err := client.Zones.Notify(ctx, "example.com"); err != nil || *result.Result != "Notification queued" {
+		t.Errorf("unexpected result %+v: %v", result, err)
+	}
+	if _, err := client.Zones.Notify(ctx, "example.org"); !errors.Is(err, powerdns.ErrValidation) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := client.Zones.Notify(ctx, "example.net"); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if result, err := client.Zones.AxfrRetrieve(ctx, "example.org"); err != nil || !strings.Contains(*result.Result, "192.0.2.1") {
+		t.Errorf("unexpected result %+v: %v", result, err)
+	}
+	if _, err := client.Zones.AxfrRetrieve(ctx, "example.com"); !errors.Is(err, powerdns.ErrValidation) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := client.Zones.AxfrRetrieve(ctx, "example.net"); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRectify(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+	if err := s.AddZone(powerdns.Zone{Name: powerdns.String("example.org."), Kind: powerdns.ZoneKindPtr(powerdns.NativeZoneKind), Presigned: powerdns.Bool(true)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result, err := client.Zones.Rectify(ctx, "example.com"); err != nil || *result.Result != "Rectified" {
+		t.Errorf("unexpected result %+v: %v", result, err)
+	}
+	if _, err := client.Zones.Rectify(ctx, "example.org"); !errors.Is(err, powerdns.ErrValidation) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := client.Zones.Rectify(ctx, "example.net"); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	rrSets := &powerdns.RRsets{Sets: []powerdns.RRset{{Name: powerdns.String("www.example.com."), Type: powerdns.RRTypePtr(powerdns.RRTypeA), TTL: powerdns.Uint32(60), ChangeType: powerdns.ChangeTypePtr(powerdns.ChangeTypeReplace), Records: []powerdns.Record{{Content: powerdns.String("192.0.2.1")}}}}}
+	if err := client.Records.Patch(ctx, "example.com", rrSets, powerdns.WithRectify()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+	addTestZone(t, s, "example.com")
+
+	export, err := client.Zones.Export(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rrSets, err := export.RRsets()
+	if err != nil || len(rrSets) != 2 {
+		t.Errorf("unexpected RRsets %+v: %v", rrSets, err)
+	}
+
+	if _, err := client.Zones.Export(ctx, "example.org"); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,508 @@
+package powerdns
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RecordData is implemented by the typed representations of record contents, e.g. MXData.
+type RecordData interface {
+	// RRType returns the resource record type of the data.
+	RRType() RRType
+
+	// String renders the data in PowerDNS presentation format, which is used as record content.
+	String() string
+}
+
+// RecordContents renders typed record data as content for RecordsService.Add and RecordsService.Change.
+func RecordContents(data ...RecordData) []string {
+	contents := make([]string, len(data))
+	for i, d := range data {
+		contents[i] = d.String()
+	}
+	return contents
+}
+
+// MXData represents the content of an MX record
+type MXData struct {
+	Preference uint16
+	Exchange   string
+}
+
+// RRType returns RRTypeMX.
+func (d MXData) RRType() RRType {
+	return RRTypeMX
+}
+
+func (d MXData) String() string {
+	return fmt.Sprintf("%d %s", d.Preference, d.Exchange)
+}
+
+// ParseMX parses the content of an MX record.
+func ParseMX(content string) (MXData, error) {
+	fields, err := splitRecordContent(RRTypeMX, content, 2)
+	if err != nil {
+		return MXData{}, err
+	}
+
+	preference, err := parseRecordUint[uint16](RRTypeMX, "preference", fields[0])
+	if err != nil {
+		return MXData{}, err
+	}
+
+	return MXData{Preference: preference, Exchange: fields[1].value}, nil
+}
+
+// SRVData represents the content of an SRV record
+type SRVData struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+}
+
+// RRType returns RRTypeSRV.
+func (d SRVData) RRType() RRType {
+	return RRTypeSRV
+}
+
+func (d SRVData) String() string {
+	return fmt.Sprintf("%d %d %d %s", d.Priority, d.Weight, d.Port, d.Target)
+}
+
+// ParseSRV parses the content of an SRV record.
+func ParseSRV(content string) (SRVData, error) {
+	fields, err := splitRecordContent(RRTypeSRV, content, 4)
+	if err != nil {
+		return SRVData{}, err
+	}
+
+	var d SRVData
+	if d.Priority, err = parseRecordUint[uint16](RRTypeSRV, "priority", fields[0]); err != nil {
+		return SRVData{}, err
+	}
+	if d.Weight, err = parseRecordUint[uint16](RRTypeSRV, "weight", fields[1]); err != nil {
+		return SRVData{}, err
+	}
+	if d.Port, err = parseRecordUint[uint16](RRTypeSRV, "port", fields[2]); err != nil {
+		return SRVData{}, err
+	}
+	d.Target = fields[3].value
+
+	return d, nil
+}
+
+// URIData represents the content of a URI record
+type URIData struct {
+	Priority uint16
+	Weight   uint16
+	Target   string
+}
+
+// RRType returns RRTypeURI.
+func (d URIData) RRType() RRType {
+	return RRTypeURI
+}
+
+func (d URIData) String() string {
+	return fmt.Sprintf("%d %d %s", d.Priority, d.Weight, quoteCharacterString(d.Target))
+}
+
+// ParseURI parses the content of a URI record.
+func ParseURI(content string) (URIData, error) {
+	fields, err := splitRecordContent(RRTypeURI, content, 3)
+	if err != nil {
+		return URIData{}, err
+	}
+
+	var d URIData
+	if d.Priority, err = parseRecordUint[uint16](RRTypeURI, "priority", fields[0]); err != nil {
+		return URIData{}, err
+	}
+	if d.Weight, err = parseRecordUint[uint16](RRTypeURI, "weight", fields[1]); err != nil {
+		return URIData{}, err
+	}
+	if d.Target, err = unquoteCharacterString(fields[2]); err != nil {
+		return URIData{}, recordContentError(RRTypeURI, content, err)
+	}
+
+	return d, nil
+}
+
+// CAAData represents the content of a CAA record
+type CAAData struct {
+	Flags uint8
+	Tag   string
+	Value string
+}
+
+// RRType returns RRTypeCAA.
+func (d CAAData) RRType() RRType {
+	return RRTypeCAA
+}
+
+func (d CAAData) String() string {
+	return fmt.Sprintf("%d %s %s", d.Flags, d.Tag, quoteCharacterString(d.Value))
+}
+
+// ParseCAA parses the content of a CAA record.
+func ParseCAA(content string) (CAAData, error) {
+	fields, err := splitRecordContent(RRTypeCAA, content, 3)
+	if err != nil {
+		return CAAData{}, err
+	}
+
+	var d CAAData
+	if d.Flags, err = parseRecordUint[uint8](RRTypeCAA, "flags", fields[0]); err != nil {
+		return CAAData{}, err
+	}
+	d.Tag = fields[1].value
+	if d.Value, err = unquoteCharacterString(fields[2]); err != nil {
+		return CAAData{}, recordContentError(RRTypeCAA, content, err)
+	}
+
+	return d, nil
+}
+
+// SOAData represents the content of an SOA record
+type SOAData struct {
+	MName   string
+	RName   string
+	Serial  uint32
+	Refresh uint32
+	Retry   uint32
+	Expire  uint32
+	Minimum uint32
+}
+
+// RRType returns RRTypeSOA.
+func (d SOAData) RRType() RRType {
+	return RRTypeSOA
+}
+
+func (d SOAData) String() string {
+	return fmt.Sprintf("%s %s %d %d %d %d %d", d.MName, d.RName, d.Serial, d.Refresh, d.Retry, d.Expire, d.Minimum)
+}
+
+// ParseSOA parses the content of an SOA record.
+func ParseSOA(content string) (SOAData, error) {
+	fields, err := splitRecordContent(RRTypeSOA, content, 7)
+	if err != nil {
+		return SOAData{}, err
+	}
+
+	d := SOAData{MName: fields[0].value, RName: fields[1].value}
+	timers := []struct {
+		name   string
+		target *uint32
+	}{
+		{"serial", &d.Serial},
+		{"refresh", &d.Refresh},
+		{"retry", &d.Retry},
+		{"expire", &d.Expire},
+		{"minimum", &d.Minimum},
+	}
+	for i, timer := range timers {
+		if *timer.target, err = parseRecordUint[uint32](RRTypeSOA, timer.name, fields[i+2]); err != nil {
+			return SOAData{}, err
+		}
+	}
+
+	return d, nil
+}
+
+// TXTData represents the content of a TXT record, which consists of one or more character strings
+type TXTData struct {
+	Strings []string
+}
+
+// RRType returns RRTypeTXT.
+func (d TXTData) RRType() RRType {
+	return RRTypeTXT
+}
+
+// String renders the character strings quoted and escaped.
+// Strings longer than 255 bytes are split, since a single character string must not exceed this limit.
+func (d TXTData) String() string {
+	quoted := make([]string, 0, len(d.Strings))
+	for _, s := range d.Strings {
+		for len(s) > 255 {
+			quoted = append(quoted, quoteCharacterString(s[:255]))
+			s = s[255:]
+		}
+		quoted = append(quoted, quoteCharacterString(s))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Text returns the concatenation of all character strings, e.g. for DKIM keys which have been split.
+func (d TXTData) Text() string {
+	return strings.Join(d.Strings, "")
+}
+
+// ParseTXT parses the content of a TXT record.
+func ParseTXT(content string) (TXTData, error) {
+	fields, err := splitRecordContent(RRTypeTXT, content, -1)
+	if err != nil {
+		return TXTData{}, err
+	}
+
+	d := TXTData{Strings: make([]string, len(fields))}
+	for i, field := range fields {
+		if d.Strings[i], err = unquoteCharacterString(field); err != nil {
+			return TXTData{}, recordContentError(RRTypeTXT, content, err)
+		}
+	}
+
+	return d, nil
+}
+
+// TLSAData represents the content of a TLSA record
+type TLSAData struct {
+	Usage        uint8
+	Selector     uint8
+	MatchingType uint8
+	Certificate  []byte
+}
+
+// RRType returns RRTypeTLSA.
+func (d TLSAData) RRType() RRType {
+	return RRTypeTLSA
+}
+
+func (d TLSAData) String() string {
+	return fmt.Sprintf("%d %d %d %s", d.Usage, d.Selector, d.MatchingType, hex.EncodeToString(d.Certificate))
+}
+
+// ParseTLSA parses the content of a TLSA record.
+func ParseTLSA(content string) (TLSAData, error) {
+	fields, err := splitRecordContent(RRTypeTLSA, content, -4)
+	if err != nil {
+		return TLSAData{}, err
+	}
+
+	var d TLSAData
+	if d.Usage, err = parseRecordUint[uint8](RRTypeTLSA, "usage", fields[0]); err != nil {
+		return TLSAData{}, err
+	}
+	if d.Selector, err = parseRecordUint[uint8](RRTypeTLSA, "selector", fields[1]); err != nil {
+		return TLSAData{}, err
+	}
+	if d.MatchingType, err = parseRecordUint[uint8](RRTypeTLSA, "matching type", fields[2]); err != nil {
+		return TLSAData{}, err
+	}
+	if d.Certificate, err = parseRecordHex(RRTypeTLSA, "certificate association data", fields[3:]); err != nil {
+		return TLSAData{}, err
+	}
+
+	return d, nil
+}
+
+// SSHFPData represents the content of an SSHFP record
+type SSHFPData struct {
+	Algorithm   uint8
+	Type        uint8
+	Fingerprint []byte
+}
+
+// RRType returns RRTypeSSHFP.
+func (d SSHFPData) RRType() RRType {
+	return RRTypeSSHFP
+}
+
+func (d SSHFPData) String() string {
+	return fmt.Sprintf("%d %d %s", d.Algorithm, d.Type, hex.EncodeToString(d.Fingerprint))
+}
+
+// ParseSSHFP parses the content of an SSHFP record.
+func ParseSSHFP(content string) (SSHFPData, error) {
+	fields, err := splitRecordContent(RRTypeSSHFP, content, -3)
+	if err != nil {
+		return SSHFPData{}, err
+	}
+
+	var d SSHFPData
+	if d.Algorithm, err = parseRecordUint[uint8](RRTypeSSHFP, "algorithm", fields[0]); err != nil {
+		return SSHFPData{}, err
+	}
+	if d.Type, err = parseRecordUint[uint8](RRTypeSSHFP, "type", fields[1]); err != nil {
+		return SSHFPData{}, err
+	}
+	if d.Fingerprint, err = parseRecordHex(RRTypeSSHFP, "fingerprint", fields[2:]); err != nil {
+		return SSHFPData{}, err
+	}
+
+	return d, nil
+}
+
+// NAPTRData represents the content of a NAPTR record
+type NAPTRData struct {
+	Order       uint16
+	Preference  uint16
+	Flags       string
+	Service     string
+	Regexp      string
+	Replacement string
+}
+
+// RRType returns RRTypeNAPTR.
+func (d NAPTRData) RRType() RRType {
+	return RRTypeNAPTR
+}
+
+func (d NAPTRData) String() string {
+	return fmt.Sprintf("%d %d %s %s %s %s", d.Order, d.Preference, quoteCharacterString(d.Flags), quoteCharacterString(d.Service), quoteCharacterString(d.Regexp), d.Replacement)
+}
+
+// ParseNAPTR parses the content of a NAPTR record.
+func ParseNAPTR(content string) (NAPTRData, error) {
+	fields, err := splitRecordContent(RRTypeNAPTR, content, 6)
+	if err != nil {
+		return NAPTRData{}, err
+	}
+
+	d := NAPTRData{Replacement: fields[5].value}
+	if d.Order, err = parseRecordUint[uint16](RRTypeNAPTR, "order", fields[0]); err != nil {
+		return NAPTRData{}, err
+	}
+	if d.Preference, err = parseRecordUint[uint16](RRTypeNAPTR, "preference", fields[1]); err != nil {
+		return NAPTRData{}, err
+	}
+	for i, target := range []*string{&d.Flags, &d.Service, &d.Regexp} {
+		if *target, err = unquoteCharacterString(fields[i+2]); err != nil {
+			return NAPTRData{}, recordContentError(RRTypeNAPTR, content, err)
+		}
+	}
+
+	return d, nil
+}
+
+// HostData represents the content of record types which consist of a single domain name, e.g. CNAME, NS or PTR
+type HostData struct {
+	Type RRType
+	Host string
+}
+
+// RRType returns the type of the record.
+func (d HostData) RRType() RRType {
+	return d.Type
+}
+
+func (d HostData) String() string {
+	return d.Host
+}
+
+func recordContentError(recordType RRType, content string, err error) error {
+	return fmt.Errorf("invalid %s content %q: %w", recordType, content, err)
+}
+
+// splitRecordContent splits content into fields.
+// A positive count requires exactly count fields, a negative count requires at least -count fields, 0 or -1 require at least one field.
+func splitRecordContent(recordType RRType, content string, count int) ([]zoneFileToken, error) {
+	entries, err := lexZoneFile(content)
+	if err != nil {
+		return nil, recordContentError(recordType, content, err)
+	}
+	if len(entries) != 1 {
+		return nil, recordContentError(recordType, content, errors.New("expected a single line"))
+	}
+
+	fields := entries[0].tokens
+	if (count > 0 && len(fields) != count) || (count < 0 && len(fields) < -count) {
+		return nil, recordContentError(recordType, content, fmt.Errorf("unexpected number of fields: %d", len(fields)))
+	}
+
+	return fields, nil
+}
+
+func parseRecordUint[T uint8 | uint16 | uint32](recordType RRType, name string, field zoneFileToken) (T, error) {
+	var zero T
+	bitSize := 32
+	switch any(zero).(type) {
+	case uint8:
+		bitSize = 8
+	case uint16:
+		bitSize = 16
+	}
+
+	value, err := strconv.ParseUint(field.value, 10, bitSize)
+	if err != nil {
+		return zero, fmt.Errorf("invalid %s %s %q", recordType, name, field.value)
+	}
+	return T(value), nil
+}
+
+func parseRecordHex(recordType RRType, name string, fields []zoneFileToken) ([]byte, error) {
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(field.value)
+	}
+
+	data, err := hex.DecodeString(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %s %q", recordType, name, b.String())
+	}
+	return data, nil
+}
+
+// quoteCharacterString renders s as quoted character string, escaping quotes, backslashes and non-printable characters.
+func quoteCharacterString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c > 0x7e:
+			_, _ = fmt.Fprintf(&b, "\\%03d", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// unquoteCharacterString reverses quoteCharacterString. Unquoted fields are accepted as well.
+func unquoteCharacterString(field zoneFileToken) (string, error) {
+	s := field.value
+	if field.quoted {
+		s = s[1 : len(s)-1]
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+
+		if i+3 < len(s) && isDigit(s[i+1]) && isDigit(s[i+2]) && isDigit(s[i+3]) {
+			value, _ := strconv.Atoi(s[i+1 : i+4])
+			if value > 255 {
+				return "", fmt.Errorf("invalid escape sequence %q", s[i:i+4])
+			}
+			b.WriteByte(byte(value))
+			i += 3
+			continue
+		}
+
+		if i+1 >= len(s) {
+			return "", errors.New("trailing backslash")
+		}
+		i++
+		b.WriteByte(s[i])
+	}
+
+	return b.String(), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
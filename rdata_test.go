@@ -0,0 +1,143 @@
+package powerdns
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecordDataRoundTrip(t *testing.T) {
+	testCases := []struct {
+		content string
+		data    RecordData
+		parse   func(string) (RecordData, error)
+	}{
+		{"10 mail.example.com.", MXData{Preference: 10, Exchange: "mail.example.com."}, func(s string) (RecordData, error) { return ParseMX(s) }},
+		{"0 5 5060 sip.example.com.", SRVData{Priority: 0, Weight: 5, Port: 5060, Target: "sip.example.com."}, func(s string) (RecordData, error) { return ParseSRV(s) }},
+		{"10 1 \"https://example.com/\"", URIData{Priority: 10, Weight: 1, Target: "https://example.com/"}, func(s string) (RecordData, error) { return ParseURI(s) }},
+		{"0 issue \"letsencrypt.org\"", CAAData{Flags: 0, Tag: "issue", Value: "letsencrypt.org"}, func(s string) (RecordData, error) { return ParseCAA(s) }},
+		{"ns1.example.com. hostmaster.example.com. 2024010101 10800 3600 604800 3600", SOAData{MName: "ns1.example.com.", RName: "hostmaster.example.com.", Serial: 2024010101, Refresh: 10800, Retry: 3600, Expire: 604800, Minimum: 3600}, func(s string) (RecordData, error) { return ParseSOA(s) }},
+		{"\"v=spf1 -all\" \"say \\\"hi\\\" \\\\ \\009\"", TXTData{Strings: []string{"v=spf1 -all", "say \"hi\" \\ \t"}}, func(s string) (RecordData, error) { return ParseTXT(s) }},
+		{"3 1 1 0123456789abcdef", TLSAData{Usage: 3, Selector: 1, MatchingType: 1, Certificate: []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}}, func(s string) (RecordData, error) { return ParseTLSA(s) }},
+		{"4 2 abcdef", SSHFPData{Algorithm: 4, Type: 2, Fingerprint: []byte{0xab, 0xcd, 0xef}}, func(s string) (RecordData, error) { return ParseSSHFP(s) }},
+		{"100 10 \"S\" \"SIP+D2U\" \"\" _sip._udp.example.com.", NAPTRData{Order: 100, Preference: 10, Flags: "S", Service: "SIP+D2U", Regexp: "", Replacement: "_sip._udp.example.com."}, func(s string) (RecordData, error) { return ParseNAPTR(s) }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.data.RRType()), func(t *testing.T) {
+			if content := tc.data.String(); content != tc.content {
+				t.Errorf("String() = %q, want %q", content, tc.content)
+			}
+
+			data, err := tc.parse(tc.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(data, tc.data) {
+				t.Errorf("parsed %#v, want %#v", data, tc.data)
+			}
+		})
+	}
+}
+
+func TestRecordDataMultiTokenHex(t *testing.T) {
+	tlsa, err := ParseTLSA("3 1 1 0123 4567")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tlsa.String() != "3 1 1 01234567" {
+		t.Errorf("unexpected content %q", tlsa.String())
+	}
+}
+
+func TestParseTXTUnquoted(t *testing.T) {
+	txt, err := ParseTXT("v=spf1 -all")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(txt.Strings, []string{"v=spf1", "-all"}) {
+		t.Errorf("unexpected strings %q", txt.Strings)
+	}
+	if txt.Text() != "v=spf1-all" {
+		t.Errorf("unexpected text %q", txt.Text())
+	}
+}
+
+func TestTXTDataSplitsLongStrings(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	content := TXTData{Strings: []string{long}}.String()
+	if content != "\""+strings.Repeat("a", 255)+"\" \""+strings.Repeat("a", 45)+"\"" {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	txt, err := ParseTXT(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if txt.Text() != long {
+		t.Error("split strings do not concatenate to the original text")
+	}
+}
+
+func TestHostData(t *testing.T) {
+	d := HostData{Type: RRTypeCNAME, Host: "www.example.com."}
+	if d.RRType() != RRTypeCNAME || d.String() != "www.example.com." {
+		t.Errorf("unexpected host data %q %q", d.RRType(), d.String())
+	}
+}
+
+func TestRecordContents(t *testing.T) {
+	contents := RecordContents(MXData{Preference: 10, Exchange: "mx1.example.com."}, MXData{Preference: 20, Exchange: "mx2.example.com."})
+	if !reflect.DeepEqual(contents, []string{"10 mx1.example.com.", "20 mx2.example.com."}) {
+		t.Errorf("unexpected contents %q", contents)
+	}
+}
+
+func TestParseRecordDataErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		parse func() error
+	}{
+		{"MX field count", func() error { _, err := ParseMX("10"); return err }},
+		{"MX preference", func() error { _, err := ParseMX("x mail.example.com."); return err }},
+		{"MX multiple lines", func() error { _, err := ParseMX("10 a.\n20 b."); return err }},
+		{"MX empty", func() error { _, err := ParseMX(""); return err }},
+		{"MX lexer", func() error { _, err := ParseMX("10 \"a"); return err }},
+		{"SRV field count", func() error { _, err := ParseSRV("0 5 5060"); return err }},
+		{"SRV priority", func() error { _, err := ParseSRV("x 5 5060 a."); return err }},
+		{"SRV weight", func() error { _, err := ParseSRV("0 x 5060 a."); return err }},
+		{"SRV port", func() error { _, err := ParseSRV("0 5 70000 a."); return err }},
+		{"URI field count", func() error { _, err := ParseURI("10 1"); return err }},
+		{"URI priority", func() error { _, err := ParseURI("x 1 \"a\""); return err }},
+		{"URI weight", func() error { _, err := ParseURI("10 x \"a\""); return err }},
+		{"URI target", func() error { _, err := ParseURI("10 1 a\\"); return err }},
+		{"CAA field count", func() error { _, err := ParseCAA("0 issue"); return err }},
+		{"CAA flags", func() error { _, err := ParseCAA("256 issue \"a\""); return err }},
+		{"CAA value", func() error { _, err := ParseCAA("0 issue \"\\999\""); return err }},
+		{"SOA field count", func() error { _, err := ParseSOA("a. b. 1 2 3 4"); return err }},
+		{"SOA timer", func() error { _, err := ParseSOA("a. b. 1 2 3 4 x"); return err }},
+		{"TXT empty", func() error { _, err := ParseTXT(""); return err }},
+		{"TXT escape", func() error { _, err := ParseTXT("a\\"); return err }},
+		{"TLSA field count", func() error { _, err := ParseTLSA("3 1 1"); return err }},
+		{"TLSA usage", func() error { _, err := ParseTLSA("x 1 1 ab"); return err }},
+		{"TLSA selector", func() error { _, err := ParseTLSA("3 x 1 ab"); return err }},
+		{"TLSA matching type", func() error { _, err := ParseTLSA("3 1 x ab"); return err }},
+		{"TLSA certificate", func() error { _, err := ParseTLSA("3 1 1 xyz"); return err }},
+		{"SSHFP field count", func() error { _, err := ParseSSHFP("4 2"); return err }},
+		{"SSHFP algorithm", func() error { _, err := ParseSSHFP("x 2 ab"); return err }},
+		{"SSHFP type", func() error { _, err := ParseSSHFP("4 x ab"); return err }},
+		{"SSHFP fingerprint", func() error { _, err := ParseSSHFP("4 2 xyz"); return err }},
+		{"NAPTR field count", func() error { _, err := ParseNAPTR("100 10 \"S\""); return err }},
+		{"NAPTR order", func() error { _, err := ParseNAPTR("x 10 \"S\" \"\" \"\" ."); return err }},
+		{"NAPTR preference", func() error { _, err := ParseNAPTR("100 x \"S\" \"\" \"\" ."); return err }},
+		{"NAPTR flags", func() error { _, err := ParseNAPTR("100 10 \"\\300\" \"\" \"\" ."); return err }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.parse(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package powerdns
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ReconcileOption is a functional option for ZonesService.Plan and ZonesService.Reconcile.
+type ReconcileOption func(*reconcileOptions)
+
+type reconcileOptions struct {
+	managedNamesOnly bool
+}
+
+// WithManagedNamesOnly restricts deletions to names which are part of the desired zone.
+// RRsets with other names are considered to be owned by other tools and are left untouched.
+func WithManagedNamesOnly() ReconcileOption {
+	return func(o *reconcileOptions) {
+		o.managedNamesOnly = true
+	}
+}
+
+// PlanAction represents a string-valued action of a planned RRset change
+type PlanAction string
+
+const (
+	// PlanActionCreate represents an RRset which does not exist yet
+	PlanActionCreate PlanAction = "create"
+	// PlanActionUpdate represents an RRset which exists with different records, TTL or comments
+	PlanActionUpdate PlanAction = "update"
+	// PlanActionDelete represents an RRset which exists but is not desired
+	PlanActionDelete PlanAction = "delete"
+)
+
+// PlannedRRsetChange describes the change of a single RRset
+type PlannedRRsetChange struct {
+	Action  PlanAction
+	Desired *RRset
+	Live    *RRset
+}
+
+// PlannedAttributeChange describes the change of a single zone attribute
+type PlannedAttributeChange struct {
+	Attribute string
+	Live      string
+	Desired   string
+}
+
+// Plan describes the changes required to transform a live zone into a desired zone
+type Plan struct {
+	Zone             string
+	RRsetChanges     []PlannedRRsetChange
+	AttributeChanges []PlannedAttributeChange
+
+	// ZoneChanges contains the zone attributes which have to be changed, or nil if there are none
+	ZoneChanges *Zone
+}
+
+// Empty reports whether the plan contains no changes.
+func (p *Plan) Empty() bool {
+	return len(p.RRsetChanges) == 0 && p.ZoneChanges == nil
+}
+
+// RRsets returns the RRset changes of the plan as payload for RecordsService.Patch.
+func (p *Plan) RRsets() *RRsets {
+	rrSets := &RRsets{}
+	for _, change := range p.RRsetChanges {
+		if change.Action == PlanActionDelete {
+			rrSets.Sets = append(rrSets.Sets, RRset{
+				Name:       change.Live.Name,
+				Type:       change.Live.Type,
+				ChangeType: ChangeTypePtr(ChangeTypeDelete),
+			})
+			continue
+		}
+
+		rrSet := *change.Desired
+		rrSet.ChangeType = ChangeTypePtr(ChangeTypeReplace)
+		rrSets.Sets = append(rrSets.Sets, rrSet)
+	}
+	return rrSets
+}
+
+// String returns a human-readable representation of the plan.
+func (p *Plan) String() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "zone %s: ", p.Zone)
+
+	if p.Empty() {
+		b.WriteString("no changes\n")
+		return b.String()
+	}
+
+	_, _ = fmt.Fprintf(&b, "%d attribute change(s), %d RRset change(s)\n", len(p.AttributeChanges), len(p.RRsetChanges))
+
+	for _, change := range p.AttributeChanges {
+		_, _ = fmt.Fprintf(&b, "  ~ %s: %s -> %s\n", change.Attribute, change.Live, change.Desired)
+	}
+
+	for _, change := range p.RRsetChanges {
+		switch change.Action {
+		case PlanActionCreate:
+			_, _ = fmt.Fprintf(&b, "  + %s\n", formatRRset(change.Desired))
+		case PlanActionUpdate:
+			_, _ = fmt.Fprintf(&b, "  ~ %s\n", formatRRset(change.Live))
+			_, _ = fmt.Fprintf(&b, "    %s\n", formatRRset(change.Desired))
+		case PlanActionDelete:
+			_, _ = fmt.Fprintf(&b, "  - %s\n", formatRRset(change.Live))
+		}
+	}
+
+	return b.String()
+}
+
+func formatRRset(rrSet *RRset) string {
+	contents := make([]string, 0, len(rrSet.Records))
+	for _, record := range rrSet.Records {
+		content := StringValue(record.Content)
+		if BoolValue(record.Disabled) {
+			content += " (disabled)"
+		}
+		contents = append(contents, content)
+	}
+
+	return fmt.Sprintf("%s %d %s [%s]", StringValue(rrSet.Name), Uint32Value(rrSet.TTL), string(*rrSet.Type), strings.Join(contents, ", "))
+}
+
+// Plan fetches the live state of the desired zone and computes the changes required to reach the desired state.
+// The desired zone must contain its name, the desired RRsets and any zone attributes which should be managed.
+// Zone attributes which are nil in the desired zone are left untouched.
+// SOA records are never deleted, and their serial is ignored when comparing them.
+func (z *ZonesService) Plan(ctx context.Context, desired *Zone, options ...ReconcileOption) (*Plan, error) {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Plan", Zone: makeDomainCanonical(StringValue(desired.Name))})
+
+	live, err := z.Get(ctx, StringValue(desired.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	return computePlan(desired, live, options...)
+}
+
+// Apply applies a plan by changing the zone attributes and patching all RRset changes at once.
+func (z *ZonesService) Apply(ctx context.Context, plan *Plan) error {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Apply", Zone: plan.Zone})
+
+	if plan.ZoneChanges != nil {
+		zoneChanges := *plan.ZoneChanges
+		if err := z.Change(ctx, plan.Zone, &zoneChanges); err != nil {
+			return err
+		}
+	}
+
+	if len(plan.RRsetChanges) == 0 {
+		return nil
+	}
+
+	return z.client.Records.Patch(ctx, plan.Zone, plan.RRsets())
+}
+
+// Reconcile computes a plan for the desired zone and applies it.
+// The returned plan describes the applied changes.
+func (z *ZonesService) Reconcile(ctx context.Context, desired *Zone, options ...ReconcileOption) (*Plan, error) {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Reconcile", Zone: makeDomainCanonical(StringValue(desired.Name))})
+
+	plan, err := z.Plan(ctx, desired, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, z.Apply(ctx, plan)
+}
+
+type rrSetKey struct {
+	name       string
+	recordType RRType
+}
+
+func newRRSetKey(rrSet *RRset) rrSetKey {
+	return rrSetKey{name: strings.ToLower(makeDomainCanonical(StringValue(rrSet.Name))), recordType: *rrSet.Type}
+}
+
+func computePlan(desired, live *Zone, options ...ReconcileOption) (*Plan, error) {
+	opts := &reconcileOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	plan := &Plan{Zone: makeDomainCanonical(StringValue(desired.Name))}
+	plan.AttributeChanges, plan.ZoneChanges = diffZoneAttributes(desired, live)
+
+	liveRRsets := make(map[rrSetKey]*RRset, len(live.RRsets))
+	for i := range live.RRsets {
+		liveRRsets[newRRSetKey(&live.RRsets[i])] = &live.RRsets[i]
+	}
+
+	desiredRRsets := make(map[rrSetKey]bool, len(desired.RRsets))
+	managedNames := make(map[string]bool, len(desired.RRsets))
+	for i := range desired.RRsets {
+		if desired.RRsets[i].Name == nil || desired.RRsets[i].Type == nil {
+			return nil, fmt.Errorf("desired RRset %d has no name or type", i)
+		}
+
+		desiredRRset := normalizeDesiredRRset(&desired.RRsets[i])
+		key := newRRSetKey(desiredRRset)
+		if desiredRRsets[key] {
+			return nil, fmt.Errorf("duplicate desired RRset %s %s", key.name, key.recordType)
+		}
+		desiredRRsets[key] = true
+		managedNames[key.name] = true
+
+		liveRRset, exists := liveRRsets[key]
+		if desiredRRset.TTL == nil {
+			if !exists {
+				return nil, fmt.Errorf("desired RRset %s %s has no TTL", key.name, key.recordType)
+			}
+			desiredRRset.TTL = liveRRset.TTL
+		}
+
+		switch {
+		case !exists:
+			plan.RRsetChanges = append(plan.RRsetChanges, PlannedRRsetChange{Action: PlanActionCreate, Desired: desiredRRset})
+		case !rrSetsEqual(desiredRRset, liveRRset):
+			plan.RRsetChanges = append(plan.RRsetChanges, PlannedRRsetChange{Action: PlanActionUpdate, Desired: desiredRRset, Live: liveRRset})
+		}
+	}
+
+	for key, liveRRset := range liveRRsets {
+		if desiredRRsets[key] || key.recordType == RRTypeSOA || (opts.managedNamesOnly && !managedNames[key.name]) {
+			continue
+		}
+		plan.RRsetChanges = append(plan.RRsetChanges, PlannedRRsetChange{Action: PlanActionDelete, Live: liveRRset})
+	}
+
+	sort.SliceStable(plan.RRsetChanges, func(i, j int) bool {
+		return lessRRSetKey(plan.RRsetChanges[i].key(), plan.RRsetChanges[j].key())
+	})
+
+	return plan, nil
+}
+
+func (c PlannedRRsetChange) key() rrSetKey {
+	if c.Desired != nil {
+		return newRRSetKey(c.Desired)
+	}
+	return newRRSetKey(c.Live)
+}
+
+func lessRRSetKey(a, b rrSetKey) bool {
+	if a.name != b.name {
+		return a.name < b.name
+	}
+	return a.recordType < b.recordType
+}
+
+func normalizeDesiredRRset(rrSet *RRset) *RRset {
+	normalized := *rrSet
+	normalized.Name = String(makeDomainCanonical(StringValue(rrSet.Name)))
+	normalized.ChangeType = nil
+	normalized.Records = slices.Clone(rrSet.Records)
+	for i := range normalized.Records {
+		if normalized.Records[i].Disabled == nil {
+			normalized.Records[i].Disabled = Bool(false)
+		}
+	}
+	fixRRSet(&normalized)
+	return &normalized
+}
+
+func rrSetsEqual(desired, live *RRset) bool {
+	if Uint32Value(desired.TTL) != Uint32Value(live.TTL) {
+		return false
+	}
+
+	if !slices.Equal(recordFingerprints(desired), recordFingerprints(live)) {
+		return false
+	}
+
+	// Comments are only managed if the desired RRset specifies them.
+	if desired.Comments != nil && !slices.Equal(commentFingerprints(desired.Comments), commentFingerprints(live.Comments)) {
+		return false
+	}
+
+	return true
+}
+
+func recordFingerprints(rrSet *RRset) []string {
+	fingerprints := make([]string, 0, len(rrSet.Records))
+	for _, record := range rrSet.Records {
+		content := StringValue(record.Content)
+		if *rrSet.Type == RRTypeSOA {
+			content = maskSOASerial(content)
+		}
+		fingerprints = append(fingerprints, fmt.Sprintf("%t %s", BoolValue(record.Disabled), content))
+	}
+	sort.Strings(fingerprints)
+	return fingerprints
+}
+
+func maskSOASerial(content string) string {
+	fields := strings.Fields(content)
+	if len(fields) != 7 {
+		return content
+	}
+	fields[2] = "0"
+	return strings.Join(fields, " ")
+}
+
+func commentFingerprints(comments []Comment) []string {
+	fingerprints := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		fingerprints = append(fingerprints, fmt.Sprintf("%q %q", StringValue(comment.Account), StringValue(comment.Content)))
+	}
+	sort.Strings(fingerprints)
+	return fingerprints
+}
+
+func diffZoneAttributes(desired, live *Zone) ([]PlannedAttributeChange, *Zone) {
+	changes := make([]PlannedAttributeChange, 0)
+	zoneChanges := &Zone{}
+
+	diffPointer(&changes, "kind", desired.Kind, live.Kind, &zoneChanges.Kind)
+	diffPointer(&changes, "account", desired.Account, live.Account, &zoneChanges.Account)
+	diffPointer(&changes, "catalog", desired.Catalog, live.Catalog, &zoneChanges.Catalog)
+	diffPointer(&changes, "dnssec", desired.DNSsec, live.DNSsec, &zoneChanges.DNSsec)
+	diffPointer(&changes, "nsec3param", desired.Nsec3Param, live.Nsec3Param, &zoneChanges.Nsec3Param)
+	diffPointer(&changes, "nsec3narrow", desired.Nsec3Narrow, live.Nsec3Narrow, &zoneChanges.Nsec3Narrow)
+	diffPointer(&changes, "soa_edit", desired.SOAEdit, live.SOAEdit, &zoneChanges.SOAEdit)
+	diffPointer(&changes, "soa_edit_api", desired.SOAEditAPI, live.SOAEditAPI, &zoneChanges.SOAEditAPI)
+	diffPointer(&changes, "api_rectify", desired.APIRectify, live.APIRectify, &zoneChanges.APIRectify)
+	diffSlice(&changes, "masters", desired.Masters, live.Masters, &zoneChanges.Masters)
+	diffSlice(&changes, "master_tsig_key_ids", desired.MasterTSIGKeyIDs, live.MasterTSIGKeyIDs, &zoneChanges.MasterTSIGKeyIDs)
+	diffSlice(&changes, "slave_tsig_key_ids", desired.SlaveTSIGKeyIDs, live.SlaveTSIGKeyIDs, &zoneChanges.SlaveTSIGKeyIDs)
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return changes, zoneChanges
+}
+
+func diffPointer[T comparable](changes *[]PlannedAttributeChange, attribute string, desired, live *T, target **T) {
+	if desired == nil || (live != nil && *desired == *live) {
+		return
+	}
+
+	liveValue := "<unset>"
+	if live != nil {
+		liveValue = fmt.Sprintf("%v", *live)
+	}
+
+	*changes = append(*changes, PlannedAttributeChange{Attribute: attribute, Live: liveValue, Desired: fmt.Sprintf("%v", *desired)})
+	*target = desired
+}
+
+func diffSlice(changes *[]PlannedAttributeChange, attribute string, desired, live []string, target *[]string) {
+	if desired == nil || slices.Equal(desired, live) {
+		return
+	}
+
+	*changes = append(*changes, PlannedAttributeChange{Attribute: attribute, Live: fmt.Sprintf("%v", live), Desired: fmt.Sprintf("%v", desired)})
+	*target = desired
+}
@@ -0,0 +1,382 @@
+package powerdns
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func generateTestLiveZone(domain string) *Zone {
+	domain = makeDomainCanonical(domain)
+	return &Zone{
+		Name:    String(domain),
+		Kind:    ZoneKindPtr(NativeZoneKind),
+		Account: String(""),
+		RRsets: []RRset{
+			{Name: String(domain), Type: RRTypePtr(RRTypeSOA), TTL: Uint32(3600), Records: []Record{{Content: String("ns1." + domain + " hostmaster." + domain + " 2024010101 10800 3600 604800 3600"), Disabled: Bool(false)}}},
+			{Name: String(domain), Type: RRTypePtr(RRTypeNS), TTL: Uint32(3600), Records: []Record{{Content: String("ns1." + domain), Disabled: Bool(false)}}},
+			{Name: String("www." + domain), Type: RRTypePtr(RRTypeA), TTL: Uint32(300), Records: []Record{{Content: String("192.0.2.1"), Disabled: Bool(false)}, {Content: String("192.0.2.2"), Disabled: Bool(false)}}},
+			{Name: String("www." + domain), Type: RRTypePtr(RRTypeTXT), TTL: Uint32(300), Records: []Record{{Content: String("\"owned by another tool\""), Disabled: Bool(false)}}},
+			{Name: String("mail." + domain), Type: RRTypePtr(RRTypeCNAME), TTL: Uint32(300), Records: []Record{{Content: String("mx." + domain), Disabled: Bool(false)}}, Comments: []Comment{{Content: String("legacy"), Account: String("ops"), ModifiedAt: Uint64(1)}}},
+			{Name: String("legacy." + domain), Type: RRTypePtr(RRTypeA), TTL: Uint32(300), Records: []Record{{Content: String("192.0.2.99"), Disabled: Bool(false)}}},
+		},
+	}
+}
+
+// generateLiveNativeZone returns the name of a zone, which is created with the RRsets of generateTestLiveZone unless mocks are used
+func generateLiveNativeZone(t *testing.T) string {
+	t.Helper()
+
+	domain := generateNativeZone(true)
+	if httpmock.Disabled() {
+		live := &RRsets{}
+		for _, rrSet := range generateTestLiveZone(domain).RRsets[2:] {
+			rrSet.ChangeType = ChangeTypePtr(ChangeTypeReplace)
+			live.Sets = append(live.Sets, rrSet)
+		}
+		if err := initialisePowerDNSTestClient().Records.Patch(context.Background(), domain, live); err != nil {
+			t.Fatalf("%s", err)
+		}
+	}
+	return domain
+}
+
+func generateTestDesiredZone(domain string) *Zone {
+	return &Zone{
+		Name:    String(domain),
+		Account: String("git"),
+		RRsets: []RRset{
+			{Name: String(domain), Type: RRTypePtr(RRTypeSOA), TTL: Uint32(3600), Records: []Record{{Content: String("ns1." + domain + ". hostmaster." + domain + ". 1 10800 3600 604800 3600")}}},
+			{Name: String(domain), Type: RRTypePtr(RRTypeNS), TTL: Uint32(3600), Records: []Record{{Content: String("ns1." + domain + ".")}}},
+			{Name: String("www." + domain), Type: RRTypePtr(RRTypeA), Records: []Record{{Content: String("192.0.2.2")}, {Content: String("192.0.2.1")}}},
+			{Name: String("mail." + domain), Type: RRTypePtr(RRTypeCNAME), TTL: Uint32(300), Records: []Record{{Content: String("mx." + domain)}}, Comments: []Comment{{Content: String("managed by git"), Account: String("git")}}},
+			{Name: String("api." + domain), Type: RRTypePtr(RRTypeAAAA), TTL: Uint32(60), Records: []Record{{Content: String("2001:db8::1")}}},
+		},
+	}
+}
+
+func TestComputePlan(t *testing.T) {
+	domain := "example.com"
+
+	t.Run("full", func(t *testing.T) {
+		plan, err := computePlan(generateTestDesiredZone(domain), generateTestLiveZone(domain))
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+
+		wantChanges := []struct {
+			action PlanAction
+			key    rrSetKey
+		}{
+			{PlanActionCreate, rrSetKey{"api.example.com.", RRTypeAAAA}},
+			{PlanActionDelete, rrSetKey{"legacy.example.com.", RRTypeA}},
+			{PlanActionUpdate, rrSetKey{"mail.example.com.", RRTypeCNAME}},
+			{PlanActionDelete, rrSetKey{"www.example.com.", RRTypeTXT}},
+		}
+		if len(plan.RRsetChanges) != len(wantChanges) {
+			t.Fatalf("Unexpected changes:\n%s", plan)
+		}
+		for i, want := range wantChanges {
+			if plan.RRsetChanges[i].Action != want.action || plan.RRsetChanges[i].key() != want.key {
+				t.Errorf("Unexpected change %d: %s %v", i, plan.RRsetChanges[i].Action, plan.RRsetChanges[i].key())
+			}
+		}
+
+		if plan.ZoneChanges == nil || StringValue(plan.ZoneChanges.Account) != "git" || plan.ZoneChanges.Kind != nil {
+			t.Errorf("Unexpected zone changes: %+v", plan.ZoneChanges)
+		}
+		if len(plan.AttributeChanges) != 1 || plan.AttributeChanges[0] != (PlannedAttributeChange{Attribute: "account", Live: "", Desired: "git"}) {
+			t.Errorf("Unexpected attribute changes: %+v", plan.AttributeChanges)
+		}
+	})
+
+	t.Run("managed names only", func(t *testing.T) {
+		plan, err := computePlan(generateTestDesiredZone(domain), generateTestLiveZone(domain), WithManagedNamesOnly())
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+
+		for _, change := range plan.RRsetChanges {
+			if change.Action == PlanActionDelete && change.key().name == "legacy.example.com." {
+				t.Error("Unmanaged name has been deleted")
+			}
+		}
+		if len(plan.RRsetChanges) != 3 {
+			t.Errorf("Unexpected changes:\n%s", plan)
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		live := generateTestLiveZone(domain)
+		desired := &Zone{Name: String(domain), RRsets: live.RRsets}
+		plan, err := computePlan(desired, live)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if !plan.Empty() {
+			t.Errorf("Unexpected changes:\n%s", plan)
+		}
+	})
+
+	t.Run("attributes", func(t *testing.T) {
+		live := &Zone{Name: String(domain), Kind: ZoneKindPtr(NativeZoneKind), Masters: []string{"192.0.2.1"}}
+		desired := &Zone{
+			Name:       String(domain),
+			Kind:       ZoneKindPtr(SlaveZoneKind),
+			DNSsec:     Bool(true),
+			Masters:    []string{"192.0.2.2"},
+			SOAEditAPI: live.SOAEditAPI,
+		}
+		plan, err := computePlan(desired, live)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+
+		wantChanges := []PlannedAttributeChange{
+			{Attribute: "kind", Live: "Native", Desired: "Slave"},
+			{Attribute: "dnssec", Live: "<unset>", Desired: "true"},
+			{Attribute: "masters", Live: "[192.0.2.1]", Desired: "[192.0.2.2]"},
+		}
+		if len(plan.AttributeChanges) != len(wantChanges) {
+			t.Fatalf("Unexpected attribute changes: %+v", plan.AttributeChanges)
+		}
+		for i := range wantChanges {
+			if plan.AttributeChanges[i] != wantChanges[i] {
+				t.Errorf("Unexpected attribute change: %+v", plan.AttributeChanges[i])
+			}
+		}
+		if *plan.ZoneChanges.Kind != SlaveZoneKind || !*plan.ZoneChanges.DNSsec || plan.ZoneChanges.Masters[0] != "192.0.2.2" {
+			t.Errorf("Unexpected zone changes: %+v", plan.ZoneChanges)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		testCases := []struct {
+			desc   string
+			rrsets []RRset
+		}{
+			{"missing type", []RRset{{Name: String("www.example.com.")}}},
+			{"missing TTL", []RRset{{Name: String("new.example.com."), Type: RRTypePtr(RRTypeA)}}},
+			{"duplicate", []RRset{{Name: String("a.example.com."), Type: RRTypePtr(RRTypeA), TTL: Uint32(1)}, {Name: String("A.example.com"), Type: RRTypePtr(RRTypeA), TTL: Uint32(1)}}},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.desc, func(t *testing.T) {
+				if _, err := computePlan(&Zone{Name: String(domain), RRsets: tc.rrsets}, generateTestLiveZone(domain)); err == nil {
+					t.Error("error is nil")
+				}
+			})
+		}
+	})
+}
+
+func TestPlanString(t *testing.T) {
+	plan, _ := computePlan(generateTestDesiredZone("example.com"), generateTestLiveZone("example.com"))
+	want := `zone example.com.: 1 attribute change(s), 4 RRset change(s)
+  ~ account:  -> git
+  + api.example.com. 60 AAAA [2001:db8::1]
+  - legacy.example.com. 300 A [192.0.2.99]
+  ~ mail.example.com. 300 CNAME [mx.example.com.]
+    mail.example.com. 300 CNAME [mx.example.com.]
+  - www.example.com. 300 TXT ["owned by another tool"]
+`
+	if plan.String() != want {
+		t.Errorf("Unexpected plan:\n%s", plan)
+	}
+
+	emptyPlan := &Plan{Zone: "example.com."}
+	if emptyPlan.String() != "zone example.com.: no changes\n" {
+		t.Errorf("Unexpected plan:\n%s", emptyPlan)
+	}
+
+	disabledRRset := &RRset{Name: String("www.example.com."), Type: RRTypePtr(RRTypeA), TTL: Uint32(1), Records: []Record{{Content: String("192.0.2.1"), Disabled: Bool(true)}}}
+	if formatRRset(disabledRRset) != "www.example.com. 1 A [192.0.2.1 (disabled)]" {
+		t.Errorf("Unexpected format: %s", formatRRset(disabledRRset))
+	}
+}
+
+func TestPlanRRsets(t *testing.T) {
+	plan, _ := computePlan(generateTestDesiredZone("example.com"), generateTestLiveZone("example.com"))
+	rrSets := plan.RRsets()
+
+	if len(rrSets.Sets) != 4 {
+		t.Fatalf("Unexpected amount of RRsets: %d", len(rrSets.Sets))
+	}
+	for i, rrSet := range rrSets.Sets {
+		wantChangeType := ChangeTypeReplace
+		if plan.RRsetChanges[i].Action == PlanActionDelete {
+			wantChangeType = ChangeTypeDelete
+			if rrSet.Records != nil {
+				t.Error("DELETE must not contain records")
+			}
+		}
+		if *rrSet.ChangeType != wantChangeType {
+			t.Errorf("Unexpected change type %s for %s", *rrSet.ChangeType, *rrSet.Name)
+		}
+	}
+}
+
+func registerReconcileMockResponder(testDomain string, patches *[]RRsets, puts *[]Zone) {
+	zoneURL := generateTestAPIVHostURL() + "/zones/" + makeDomainCanonical(testDomain)
+
+	httpmock.RegisterResponder(http.MethodGet, zoneURL,
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, generateTestLiveZone(testDomain))
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodPatch, zoneURL,
+		func(req *http.Request) (*http.Response, error) {
+			var rrSets RRsets
+			_ = json.NewDecoder(req.Body).Decode(&rrSets)
+			*patches = append(*patches, rrSets)
+			return httpmock.NewBytesResponse(http.StatusNoContent, []byte{}), nil
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodPut, zoneURL,
+		func(req *http.Request) (*http.Response, error) {
+			var zone Zone
+			_ = json.NewDecoder(req.Body).Decode(&zone)
+			*puts = append(*puts, zone)
+			if StringValue(zone.Account) == "forbidden" {
+				return httpmock.NewJsonResponse(http.StatusUnprocessableEntity, Error{Message: "Forbidden account"})
+			}
+			return httpmock.NewBytesResponse(http.StatusNoContent, []byte{}), nil
+		},
+	)
+}
+
+func TestReconcile(t *testing.T) {
+	testDomain := generateLiveNativeZone(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerReconcileMockResponder(testDomain, &[]RRsets{}, &[]Zone{})
+
+	var patches []RRsets
+	var puts []Zone
+	p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithMiddleware(recordPayloads(http.MethodPatch, &patches), recordPayloads(http.MethodPut, &puts)))
+	plan, err := p.Zones.Reconcile(context.Background(), generateTestDesiredZone(testDomain), WithManagedNamesOnly())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if len(patches) != 1 || len(patches[0].Sets) != len(plan.RRsetChanges) {
+		t.Errorf("Expected a single PATCH with %d RRsets, got %v", len(plan.RRsetChanges), patches)
+	}
+	if len(puts) != 1 || StringValue(puts[0].Account) != "git" || puts[0].Name != nil {
+		t.Errorf("Expected a single PUT with zone changes, got %v", puts)
+	}
+	if !strings.Contains(plan.String(), "+ api."+makeDomainCanonical(testDomain)) {
+		t.Errorf("Unexpected plan:\n%s", plan)
+	}
+}
+
+func TestApplyEmptyPlan(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	p := initialisePowerDNSTestClient()
+	if err := p.Zones.Apply(context.Background(), &Plan{Zone: "example.com."}); err != nil {
+		t.Errorf("%s", err)
+	}
+	if httpmock.GetTotalCallCount() != 0 {
+		t.Error("Empty plan must not send requests")
+	}
+}
+
+func TestApplyError(t *testing.T) {
+	testDomain := generateNativeZone(false)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	patches := make([]RRsets, 0)
+	puts := make([]Zone, 0)
+	registerReconcileMockResponder(testDomain, &patches, &puts)
+
+	p := initialisePowerDNSTestClient()
+	desired := generateTestDesiredZone(testDomain)
+	desired.Account = String("forbidden")
+	if _, err := p.Zones.Reconcile(context.Background(), desired); err == nil {
+		t.Error("error is nil")
+	}
+	if len(patches) != 0 {
+		t.Error("RRsets must not be patched if the zone change failed")
+	}
+}
+
+func TestReconcileError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if _, err := p.Zones.Reconcile(context.Background(), generateTestDesiredZone("example.com")); err == nil {
+		t.Error("error is nil")
+	}
+	if _, err := p.Zones.Plan(context.Background(), generateTestDesiredZone("example.com")); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestRRsetsEqual(t *testing.T) {
+	base := RRset{Name: String("www.example.com."), Type: RRTypePtr(RRTypeA), TTL: Uint32(300), Records: []Record{{Content: String("192.0.2.1")}}}
+
+	differentTTL := base
+	differentTTL.TTL = Uint32(60)
+
+	differentRecords := base
+	differentRecords.Records = []Record{{Content: String("192.0.2.1"), Disabled: Bool(true)}}
+
+	withComments := base
+	withComments.Comments = []Comment{{Content: String("foo")}}
+
+	testCases := []struct {
+		desc    string
+		desired RRset
+		want    bool
+	}{
+		{"equal", base, true},
+		{"different TTL", differentTTL, false},
+		{"different records", differentRecords, false},
+		{"different comments", withComments, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if rrSetsEqual(&tc.desired, &base) != tc.want {
+				t.Errorf("rrSetsEqual returned %t", !tc.want)
+			}
+		})
+	}
+}
+
+func TestMaskSOASerial(t *testing.T) {
+	testCases := []struct {
+		content     string
+		wantContent string
+	}{
+		{"ns1.example.com. hostmaster.example.com. 2024010101 10800 3600 604800 3600", "ns1.example.com. hostmaster.example.com. 0 10800 3600 604800 3600"},
+		{"invalid", "invalid"},
+	}
+
+	for _, tc := range testCases {
+		if maskSOASerial(tc.content) != tc.wantContent {
+			t.Errorf("Unexpected content: %s", maskSOASerial(tc.content))
+		}
+	}
+}
+
+func TestLessRRSetKey(t *testing.T) {
+	if !lessRRSetKey(rrSetKey{"a.example.com.", RRTypeTXT}, rrSetKey{"b.example.com.", RRTypeA}) {
+		t.Error("Names must be compared first")
+	}
+	if !lessRRSetKey(rrSetKey{"a.example.com.", RRTypeA}, rrSetKey{"a.example.com.", RRTypeTXT}) {
+		t.Error("Types must be compared second")
+	}
+}
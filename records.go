@@ -2,9 +2,12 @@ package powerdns
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
-	"net/url"
 	"path"
+	"slices"
+	"strings"
 )
 
 // RecordsService handles communication with the records related methods of the Client API
@@ -20,6 +23,19 @@ type RRset struct {
 	Comments   []Comment   `json:"comments,omitempty"`
 }
 
+// MarshalJSON omits nil comments, which leaves the comments of an RRset unchanged, but sends empty comments in order to clear them.
+func (r RRset) MarshalJSON() ([]byte, error) {
+	type rrSet RRset
+	if r.Comments == nil || len(r.Comments) > 0 {
+		return json.Marshal(rrSet(r))
+	}
+
+	return json.Marshal(struct {
+		rrSet
+		Comments []Comment `json:"comments"`
+	}{rrSet(r), r.Comments})
+}
+
 // Record structure with JSON API metadata
 type Record struct {
 	Content  *string `json:"content,omitempty"`
@@ -174,11 +190,15 @@ func WithComments(comments ...Comment) func(*RRset) {
 
 // Add creates a new resource record
 func (r *RecordsService) Add(ctx context.Context, domain string, name string, recordType RRType, ttl uint32, content []string, options ...func(*RRset)) error {
+	ctx = withOperation(ctx, Operation{Service: "Records", Method: "Add", Zone: makeDomainCanonical(domain), RRsetName: makeDomainCanonical(name), RRsetType: recordType})
+
 	return r.Change(ctx, domain, name, recordType, ttl, content, options...)
 }
 
 // Change replaces an existing resource record
 func (r *RecordsService) Change(ctx context.Context, domain string, name string, recordType RRType, ttl uint32, content []string, options ...func(*RRset)) error {
+	ctx = withOperation(ctx, Operation{Service: "Records", Method: "Change", Zone: makeDomainCanonical(domain), RRsetName: makeDomainCanonical(name), RRsetType: recordType})
+
 	rrset := new(RRset)
 	rrset.Name = &name
 	rrset.Type = &recordType
@@ -199,6 +219,8 @@ func (r *RecordsService) Change(ctx context.Context, domain string, name string,
 
 // Delete removes an existing resource record
 func (r *RecordsService) Delete(ctx context.Context, domain string, name string, recordType RRType) error {
+	ctx = withOperation(ctx, Operation{Service: "Records", Method: "Delete", Zone: makeDomainCanonical(domain), RRsetName: makeDomainCanonical(name), RRsetType: recordType})
+
 	rrset := new(RRset)
 	rrset.Name = &name
 	rrset.Type = &recordType
@@ -208,44 +230,224 @@ func (r *RecordsService) Delete(ctx context.Context, domain string, name string,
 	return r.patchRRSet(ctx, domain, payload)
 }
 
+// maxRecordUpdateAttempts limits how often AddRecord and RemoveRecord write the RRset if a concurrent writer keeps undoing the update
+const maxRecordUpdateAttempts = 5
+
+// AddRecord appends a single record to an RRset, which is created with ttl if it does not exist yet.
+// The TTL of an existing RRset as well as its other records and comments are preserved, and adding existing content is a no-op.
+// The RRset is read again right before it is written, and ErrConcurrentModification is returned if it has been changed in the meantime.
+// It is read again after it has been written, and the update is applied again if a concurrent writer has removed the record.
+// Since PowerDNS does not support conditional writes, this protection is best-effort: a change between the last read and the write is overwritten.
+func (r *RecordsService) AddRecord(ctx context.Context, domain string, name string, recordType RRType, ttl uint32, content string) error {
+	ctx = withOperation(ctx, Operation{Service: "Records", Method: "AddRecord", Zone: makeDomainCanonical(domain), RRsetName: makeDomainCanonical(name), RRsetType: recordType})
+
+	content = canonicalRecordContent(recordType, content)
+	return r.updateRRSet(ctx, domain, name, recordType, func(rrSet *RRset) bool {
+		if indexRecord(rrSet, content) >= 0 {
+			return false
+		}
+
+		if rrSet.TTL == nil {
+			rrSet.TTL = &ttl
+		}
+		rrSet.Records = append(rrSet.Records, Record{Content: String(content), Disabled: Bool(false)})
+		return true
+	})
+}
+
+// RemoveRecord removes a single record from an RRset, preserving its other records and comments.
+// Removing the last record deletes the RRset, and removing absent content is a no-op.
+// Concurrent changes are handled like by AddRecord, on a best-effort basis.
+func (r *RecordsService) RemoveRecord(ctx context.Context, domain string, name string, recordType RRType, content string) error {
+	ctx = withOperation(ctx, Operation{Service: "Records", Method: "RemoveRecord", Zone: makeDomainCanonical(domain), RRsetName: makeDomainCanonical(name), RRsetType: recordType})
+
+	content = canonicalRecordContent(recordType, content)
+	return r.updateRRSet(ctx, domain, name, recordType, func(rrSet *RRset) bool {
+		i := indexRecord(rrSet, content)
+		if i < 0 {
+			return false
+		}
+
+		rrSet.Records = slices.Delete(rrSet.Records, i, i+1)
+		return true
+	})
+}
+
+// updateRRSet applies update to a copy of the live RRset and writes the result, until update reports that nothing has to be changed.
+// Writes replace the whole RRset, so it is compared with a fresh read right before each write in order to detect concurrent changes,
+// and it is read again after each write in order to verify that no concurrent writer has undone the update.
+func (r *RecordsService) updateRRSet(ctx context.Context, domain string, name string, recordType RRType, update func(*RRset) bool) error {
+	for attempt := 0; ; attempt++ {
+		live, err := r.getRRSet(ctx, domain, name, recordType)
+		if err != nil {
+			return err
+		}
+
+		rrSet := live
+		rrSet.Records = slices.Clone(live.Records)
+		if !update(&rrSet) {
+			return nil
+		}
+		if attempt == maxRecordUpdateAttempts {
+			return ErrConcurrentModification
+		}
+
+		current, err := r.getRRSet(ctx, domain, name, recordType)
+		if err != nil {
+			return err
+		}
+		if !rrSetsEqual(&live, &current) {
+			return fmt.Errorf("%w: %s %s has been changed while it was updated", ErrConcurrentModification, makeDomainCanonical(name), recordType)
+		}
+
+		if len(rrSet.Records) == 0 {
+			rrSet = RRset{Name: rrSet.Name, Type: rrSet.Type, ChangeType: ChangeTypePtr(ChangeTypeDelete)}
+		} else {
+			rrSet.ChangeType = ChangeTypePtr(ChangeTypeReplace)
+		}
+
+		if err := r.patchRRSet(ctx, domain, r.prepareRRSet(&rrSet)); err != nil {
+			return err
+		}
+	}
+}
+
+// getRRSet retrieves a single RRset. If it does not exist, an RRset without records is returned.
+func (r *RecordsService) getRRSet(ctx context.Context, domain string, name string, recordType RRType) (RRset, error) {
+	rrSets, err := r.Get(ctx, domain, makeDomainCanonical(name), &recordType)
+	if err != nil {
+		return RRset{}, err
+	}
+
+	wantRRSet := RRset{Name: String(makeDomainCanonical(name)), Type: &recordType}
+	for _, rrSet := range rrSets {
+		if rrSet.Name != nil && rrSet.Type != nil && newRRSetKey(&rrSet) == newRRSetKey(&wantRRSet) {
+			return rrSet, nil
+		}
+	}
+
+	return wantRRSet, nil
+}
+
+func indexRecord(rrSet *RRset, content string) int {
+	return slices.IndexFunc(rrSet.Records, func(record Record) bool {
+		return record.Content != nil && canonicalRecordContent(*rrSet.Type, *record.Content) == content
+	})
+}
+
 // Get retrieves rrsets with name and recordType (if provided)
 func (r *RecordsService) Get(ctx context.Context, domain, name string, recordType *RRType) ([]RRset, error) {
-	query := &url.Values{}
-	query.Add("rrset_name", name)
+	ctx = withOperation(ctx, Operation{Service: "Records", Method: "Get", Zone: makeDomainCanonical(domain), RRsetName: makeDomainCanonical(name)})
 
+	options := []GetOption{WithRRsetName(name)}
 	if recordType != nil {
-		query.Add("rrset_type", string(*recordType))
+		options = append(options, WithRRsetType(*recordType))
 	}
 
-	req, err := r.client.newRequest(ctx, http.MethodGet, path.Join("servers", r.client.VHost, "zones", makeDomainCanonical(domain)), query, nil)
+	zone, err := r.client.Zones.Get(ctx, domain, options...)
 	if err != nil {
 		return nil, err
 	}
+	return zone.RRsets, nil
+}
+
+// PatchOption is a functional option for RecordsService.Patch.
+type PatchOption func(*patchOptions)
+
+type patchOptions struct {
+	rectify bool
+}
 
-	var zone Zone
-	_, err = r.client.do(req, &zone)
-	return zone.RRsets, err
+// WithRectify rectifies the zone after the patch has been applied, unless PowerDNS already does so because of the zone's APIRectify flag.
+func WithRectify() PatchOption {
+	return func(o *patchOptions) {
+		o.rectify = true
+	}
 }
 
 // Patch method makes patch of already prepared rrsets
-func (r *RecordsService) Patch(ctx context.Context, domain string, rrSets *RRsets) error {
+func (r *RecordsService) Patch(ctx context.Context, domain string, rrSets *RRsets, options ...PatchOption) error {
+	ctx = withOperation(ctx, Operation{Service: "Records", Method: "Patch", Zone: makeDomainCanonical(domain)})
+
+	var o patchOptions
+	for _, option := range options {
+		option(&o)
+	}
+
 	for i := range rrSets.Sets {
 		fixRRSet(&rrSets.Sets[i])
 	}
-	return r.patchRRSet(ctx, domain, rrSets)
+	if err := r.patchRRSet(ctx, domain, rrSets); err != nil || !o.rectify {
+		return err
+	}
+
+	zone, err := r.client.Zones.Get(ctx, domain, WithoutRRsets())
+	if err == nil && !BoolValue(zone.APIRectify) {
+		_, err = r.client.Zones.Rectify(ctx, domain)
+	}
+	if err != nil {
+		return fmt.Errorf("patch has been applied, but the zone has not been rectified: %w", err)
+	}
+	return nil
 }
 
-func canonicalResourceRecordValues(records []Record) {
-	for i := range records {
-		records[i].Content = String(makeDomainCanonical(*records[i].Content))
+// rdataDomainNameFields lists the positions of domain names within the record content of each RR type
+var rdataDomainNameFields = map[RRType][]int{
+	RRTypeAFSDB: {1},
+	RRTypeALIAS: {0},
+	RRTypeCNAME: {0},
+	RRTypeDNAME: {0},
+	RRTypeKX:    {1},
+	RRTypeMINFO: {0, 1},
+	RRTypeMR:    {0},
+	RRTypeMX:    {1},
+	RRTypeNAPTR: {5},
+	RRTypeNS:    {0},
+	RRTypePTR:   {0},
+	RRTypeRP:    {0, 1},
+	RRTypeSOA:   {0, 1},
+	RRTypeSRV:   {3},
+}
+
+// canonicalRecordContent makes the domain names within content fully qualified, depending on the record type.
+// Null targets (".") and quoted fields are left intact, as is content which cannot be split into fields.
+func canonicalRecordContent(recordType RRType, content string) string {
+	positions, ok := rdataDomainNameFields[recordType]
+	if !ok {
+		return content
+	}
+
+	entries, err := lexZoneFile(content)
+	if err != nil || len(entries) != 1 {
+		return content
 	}
+
+	tokens := entries[0].tokens
+	fields := make([]string, len(tokens))
+	for i, token := range tokens {
+		fields[i] = token.value
+	}
+
+	for _, i := range positions {
+		if i >= len(tokens) || tokens[i].quoted || tokens[i].value == "." {
+			continue
+		}
+		fields[i] = makeDomainCanonical(tokens[i].value)
+	}
+
+	return strings.Join(fields, " ")
 }
 
 func fixRRSet(rrset *RRset) {
-	if *rrset.Type != RRTypeCNAME && *rrset.Type != RRTypeMX {
+	if rrset.Type == nil {
 		return
 	}
-	canonicalResourceRecordValues(rrset.Records)
+
+	for i := range rrset.Records {
+		if rrset.Records[i].Content != nil {
+			rrset.Records[i].Content = String(canonicalRecordContent(*rrset.Type, *rrset.Records[i].Content))
+		}
+	}
 }
 
 func (r *RecordsService) prepareRRSet(rrSet *RRset) *RRsets {
@@ -259,7 +461,7 @@ func (r *RecordsService) prepareRRSet(rrSet *RRset) *RRsets {
 }
 
 func (r *RecordsService) patchRRSet(ctx context.Context, domain string, rrSets *RRsets) error {
-	req, err := r.client.newRequest(ctx, http.MethodPatch, path.Join("servers", r.client.VHost, "zones", makeDomainCanonical(domain)), nil, &rrSets)
+	req, err := r.client.newRequest(ctx, http.MethodPatch, path.Join("servers", r.client.VHost, "zones", zoneID(domain)), nil, &rrSets)
 	if err != nil {
 		return err
 	}
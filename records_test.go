@@ -3,12 +3,14 @@ package powerdns
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
 	"net/http"
 	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"testing"
@@ -347,26 +349,38 @@ func TestDeleteRecordError(t *testing.T) {
 	}
 }
 
-func TestCanonicalResourceRecordValues(t *testing.T) {
+func TestCanonicalRecordContent(t *testing.T) {
 	testCases := []struct {
-		records     []Record
-		wantContent []string
+		recordType  RRType
+		content     string
+		wantContent string
 	}{
-		{[]Record{{Content: String("foo.tld")}}, []string{"foo.tld."}},
-		{[]Record{{Content: String("foo.tld.")}}, []string{"foo.tld."}},
-		{[]Record{{Content: String("foo.tld")}, {Content: String("foo.tld.")}}, []string{"foo.tld.", "foo.tld."}},
+		{RRTypeCNAME, "foo.tld", "foo.tld."},
+		{RRTypeCNAME, "foo.tld.", "foo.tld."},
+		{RRTypeMX, "10 foo.tld", "10 foo.tld."},
+		{RRTypeMX, "0 .", "0 ."},
+		{RRTypeSRV, "0 5 5060 sip.foo.tld", "0 5 5060 sip.foo.tld."},
+		{RRTypeSRV, "0 0 0 .", "0 0 0 ."},
+		{RRTypeNS, "ns1.foo.tld", "ns1.foo.tld."},
+		{RRTypePTR, "host.foo.tld", "host.foo.tld."},
+		{RRTypeDNAME, "bar.tld", "bar.tld."},
+		{RRTypeALIAS, "lb.foo.tld", "lb.foo.tld."},
+		{RRTypeSOA, "ns1.foo.tld hostmaster.foo.tld 1 10800 3600 604800 3600", "ns1.foo.tld. hostmaster.foo.tld. 1 10800 3600 604800 3600"},
+		{RRTypeNAPTR, `100 10 "S" "SIP+D2U" "" _sip._udp.foo.tld`, `100 10 "S" "SIP+D2U" "" _sip._udp.foo.tld.`},
+		{RRTypeKX, "10 kx.foo.tld", "10 kx.foo.tld."},
+		{RRTypeAFSDB, "1 afs.foo.tld", "1 afs.foo.tld."},
+		{RRTypeRP, "admin.foo.tld txt.foo.tld", "admin.foo.tld. txt.foo.tld."},
+		{RRTypeA, "127.0.0.1", "127.0.0.1"},
+		{RRTypeTXT, `"foo.tld"`, `"foo.tld"`},
+		{RRTypeMX, "foo.tld", "foo.tld"},
+		{RRTypeMX, `10 "foo.tld"`, `10 "foo.tld"`},
+		{RRTypeMX, "10 \"foo.tld", "10 \"foo.tld"},
 	}
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("TestCase%d", i), func(t *testing.T) {
-			canonicalResourceRecordValues(tc.records)
-
-			for j := range tc.records {
-				isContent := *tc.records[j].Content
-				wantContent := tc.wantContent[j]
-				if isContent != wantContent {
-					t.Errorf("Comparison failed: %s != %s", isContent, wantContent)
-				}
+			if content := canonicalRecordContent(tc.recordType, tc.content); content != tc.wantContent {
+				t.Errorf("Comparison failed: %s != %s", content, tc.wantContent)
 			}
 		})
 	}
@@ -377,7 +391,9 @@ func TestFixRRset(t *testing.T) {
 		rrset                     RRset
 		wantFixedCanonicalRecords bool
 	}{
-		{RRset{Type: RRTypePtr(RRTypeMX), Records: []Record{{Content: String("foo.tld")}}}, true},
+		{RRset{Type: RRTypePtr(RRTypeMX), Records: []Record{{Content: String("10 foo.tld")}}}, true},
+		{RRset{Type: RRTypePtr(RRTypeNS), Records: []Record{{Content: String("foo.tld")}}}, true},
+		{RRset{Records: []Record{{Content: String("foo.tld")}}}, false},
 		{RRset{Type: RRTypePtr(RRTypeCNAME), Records: []Record{{Content: String("foo.tld")}}}, true},
 		{RRset{Type: RRTypePtr(RRTypeA), Records: []Record{{Content: String("foo.tld")}}}, false},
 	}
@@ -407,6 +423,14 @@ func TestFixRRset(t *testing.T) {
 	}
 }
 
+func TestFixRRsetWithoutContent(t *testing.T) {
+	rrset := RRset{Type: RRTypePtr(RRTypeCNAME), Records: []Record{{Disabled: Bool(true)}}}
+	fixRRSet(&rrset)
+	if rrset.Records[0].Content != nil {
+		t.Error("content has been set")
+	}
+}
+
 func TestGetRecord(t *testing.T) {
 	testDomain := generateNativeZone(true)
 	httpmock.Activate()
@@ -555,3 +579,434 @@ func TestPatchRRSets(t *testing.T) {
 		t.Errorf("%s", err)
 	}
 }
+
+func TestPatchRRSetsWithRectify(t *testing.T) {
+	testDomain := generateNativeZone(true)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var requests []string
+	p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithMiddleware(recordRequests(&requests)))
+
+	testRecordName := generateTestRecord(p, testDomain, true, testRecordTXT)
+	registerRecordMockResponder(testDomain, testRecordName)
+	registerZoneMockResponder(testDomain, NativeZoneKind)
+	if err := p.Zones.Change(context.Background(), testDomain, &Zone{APIRectify: Bool(false)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rectifyRequest := "PUT /api/v1/servers/" + testVHost + "/zones/" + makeDomainCanonical(testDomain) + "/rectify"
+	countRectifications := func() int {
+		var count int
+		for _, request := range requests {
+			if request == rectifyRequest {
+				count++
+			}
+		}
+		return count
+	}
+
+	rrSets := RRsets{Sets: []RRset{{Name: String(makeDomainCanonical(testRecordName)), Type: RRTypePtr(RRTypeTXT), ChangeType: ChangeTypePtr(ChangeTypeDelete)}}}
+
+	if err := p.Records.Patch(context.Background(), testDomain, &rrSets); err != nil {
+		t.Errorf("%s", err)
+	}
+	if calls := countRectifications(); calls != 0 {
+		t.Errorf("zone has been rectified %d times without WithRectify", calls)
+	}
+
+	if err := p.Records.Patch(context.Background(), testDomain, &rrSets, WithRectify()); err != nil {
+		t.Errorf("%s", err)
+	}
+	if calls := countRectifications(); calls != 1 {
+		t.Errorf("zone has been rectified %d times", calls)
+	}
+
+	if err := p.Zones.Change(context.Background(), testDomain, &Zone{APIRectify: Bool(true)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/zones/"+makeDomainCanonical(testDomain),
+		func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("rrsets") != "false" {
+				return httpmock.NewStringResponse(http.StatusBadRequest, "RRsets have been requested"), nil
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, Zone{Name: String(makeDomainCanonical(testDomain)), APIRectify: Bool(true)})
+		},
+	)
+	if err := p.Records.Patch(context.Background(), testDomain, &rrSets, WithRectify()); err != nil {
+		t.Errorf("%s", err)
+	}
+	if calls := countRectifications(); calls != 1 {
+		t.Errorf("zone with APIRectify has been rectified %d times", calls)
+	}
+}
+
+func TestPatchRRSetsWithRectifyError(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("rectify failures are simulated by mocks")
+	}
+	testDomain := generateNativeZone(true)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	p := initialisePowerDNSTestClient()
+
+	testRecordName := generateTestRecord(p, testDomain, true, testRecordTXT)
+	registerRecordMockResponder(testDomain, testRecordName)
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/zones/"+makeDomainCanonical(testDomain),
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusOK, Zone{Name: String(makeDomainCanonical(testDomain)), Presigned: Bool(true)})
+		},
+	)
+	httpmock.RegisterResponder(http.MethodPut, generateTestAPIVHostURL()+"/zones/"+makeDomainCanonical(testDomain)+"/rectify",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusUnprocessableEntity, Error{Message: "Zone '" + makeDomainCanonical(testDomain) + "' is pre-signed, not rectifying."})
+		},
+	)
+
+	rrSets := RRsets{Sets: []RRset{{Name: String(testRecordName), Type: RRTypePtr(RRTypeTXT), ChangeType: ChangeTypePtr(ChangeTypeDelete)}}}
+	if err := p.Records.Patch(context.Background(), testDomain, &rrSets, WithRectify()); !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), "not been rectified") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	testDomainWithoutZone := generateNativeZone(true)
+	registerRecordMockResponder(testDomainWithoutZone, testRecordName)
+	if err := p.Records.Patch(context.Background(), testDomainWithoutZone, &rrSets, WithRectify()); err == nil || !strings.Contains(err.Error(), "not been rectified") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// registerRecordStoreMockResponder serves the RRsets of a zone and applies PATCH requests to them.
+// onGet is called before each GET request, e.g. to simulate concurrent modifications.
+func registerRecordStoreMockResponder(testDomain string, rrSets *[]RRset, patches *[]RRsets, onGet func(getCount int) *http.Response) {
+	testDomainCanonical := makeDomainCanonical(testDomain)
+	getCount := 0
+
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/zones/"+testDomainCanonical,
+		func(req *http.Request) (*http.Response, error) {
+			getCount++
+			if res := onGet(getCount); res != nil {
+				return res, nil
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, Zone{Name: String(testDomainCanonical), RRsets: *rrSets})
+		},
+	)
+	httpmock.RegisterResponder(http.MethodPatch, generateTestAPIVHostURL()+"/zones/"+testDomainCanonical,
+		func(req *http.Request) (*http.Response, error) {
+			var payload RRsets
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				return httpmock.NewBytesResponse(http.StatusBadRequest, []byte{}), nil
+			}
+			*patches = append(*patches, payload)
+
+			for _, change := range payload.Sets {
+				*rrSets = slices.DeleteFunc(*rrSets, func(rrSet RRset) bool {
+					return *rrSet.Name == *change.Name && *rrSet.Type == *change.Type
+				})
+				if *change.ChangeType == ChangeTypeReplace {
+					change.ChangeType = nil
+					*rrSets = append(*rrSets, change)
+				}
+			}
+			return httpmock.NewBytesResponse(http.StatusNoContent, []byte{}), nil
+		},
+	)
+}
+
+func generateTestRecordStore(domain string) []RRset {
+	domain = makeDomainCanonical(domain)
+	return []RRset{
+		{
+			Name: String("www." + domain),
+			Type: RRTypePtr(RRTypeA),
+			TTL:  Uint32(300),
+			Records: []Record{
+				{Content: String("192.0.2.1"), Disabled: Bool(false)},
+				{Content: String("192.0.2.2"), Disabled: Bool(true)},
+			},
+			Comments: []Comment{{Content: String("managed by DHCP"), Account: String("dhcp"), ModifiedAt: Uint64(1)}},
+		},
+		{
+			Name:    String("www." + domain),
+			Type:    RRTypePtr(RRTypeAAAA),
+			TTL:     Uint32(300),
+			Records: []Record{{Content: String("2001:db8::1"), Disabled: Bool(false)}},
+		},
+		{
+			Name:    String(domain),
+			Type:    RRTypePtr(RRTypeMX),
+			TTL:     Uint32(3600),
+			Records: []Record{{Content: String("10 mx1." + domain), Disabled: Bool(false)}},
+		},
+	}
+}
+
+// generateRecordStoreZone returns the name of a zone and a client, which records the PATCH requests to the zone in patches.
+// The zone contains the RRsets of generateTestRecordStore, which are served by mocks or created on the server if mocks are disabled.
+func generateRecordStoreZone(t *testing.T, patches *[]RRsets) (string, *Client) {
+	t.Helper()
+
+	domain := generateNativeZone(true)
+	rrSets := generateTestRecordStore(domain)
+	if httpmock.Disabled() {
+		store := &RRsets{}
+		for _, rrSet := range rrSets {
+			rrSet.ChangeType = ChangeTypePtr(ChangeTypeReplace)
+			store.Sets = append(store.Sets, rrSet)
+		}
+		if err := initialisePowerDNSTestClient().Records.Patch(context.Background(), domain, store); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	registerRecordStoreMockResponder(domain, &rrSets, &[]RRsets{}, func(int) *http.Response { return nil })
+
+	return domain, New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithMiddleware(recordPayloads(http.MethodPatch, patches)))
+}
+
+func TestAddSingleRecord(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var patches []RRsets
+	testDomain, p := generateRecordStoreZone(t, &patches)
+	if err := p.Records.AddRecord(context.Background(), testDomain, "www."+testDomain, RRTypeA, 60, "192.0.2.3"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(patches) != 1 || len(patches[0].Sets) != 1 {
+		t.Fatalf("unexpected patches %+v", patches)
+	}
+	rrSet := patches[0].Sets[0]
+	if *rrSet.ChangeType != ChangeTypeReplace || *rrSet.TTL != 300 {
+		t.Errorf("unexpected change type %s or TTL %d", *rrSet.ChangeType, *rrSet.TTL)
+	}
+	wantRecords := []Record{
+		{Content: String("192.0.2.1"), Disabled: Bool(false)},
+		{Content: String("192.0.2.2"), Disabled: Bool(true)},
+		{Content: String("192.0.2.3"), Disabled: Bool(false)},
+	}
+	if !reflect.DeepEqual(rrSet.Records, wantRecords) {
+		t.Errorf("unexpected records %+v", rrSet.Records)
+	}
+	if len(rrSet.Comments) != 1 || *rrSet.Comments[0].Content != "managed by DHCP" {
+		t.Errorf("comments have not been preserved: %+v", rrSet.Comments)
+	}
+}
+
+func TestAddRecordNewRRset(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var patches []RRsets
+	testDomain, p := generateRecordStoreZone(t, &patches)
+	if err := p.Records.AddRecord(context.Background(), testDomain, "mail."+testDomain, RRTypeMX, 60, "10 mx."+testDomain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(patches) != 1 {
+		t.Fatalf("unexpected patches %+v", patches)
+	}
+	rrSet := patches[0].Sets[0]
+	if *rrSet.Name != makeDomainCanonical("mail."+testDomain) || *rrSet.TTL != 60 || *rrSet.Records[0].Content != "10 "+makeDomainCanonical("mx."+testDomain) {
+		t.Errorf("unexpected RRset %+v", rrSet)
+	}
+}
+
+func TestAddRecordExisting(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var patches []RRsets
+	testDomain, p := generateRecordStoreZone(t, &patches)
+	if err := p.Records.AddRecord(context.Background(), testDomain, testDomain, RRTypeMX, 60, "10 mx1."+testDomain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(patches) != 0 {
+		t.Errorf("unexpected patches %+v", patches)
+	}
+}
+
+func TestRemoveRecord(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var patches []RRsets
+	testDomain, p := generateRecordStoreZone(t, &patches)
+	if err := p.Records.RemoveRecord(context.Background(), testDomain, "www."+testDomain, RRTypeA, "192.0.2.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Records.RemoveRecord(context.Background(), testDomain, "www."+testDomain, RRTypeA, "192.0.2.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(patches) != 1 {
+		t.Fatalf("unexpected patches %+v", patches)
+	}
+	rrSet := patches[0].Sets[0]
+	if *rrSet.ChangeType != ChangeTypeReplace || !reflect.DeepEqual(rrSet.Records, []Record{{Content: String("192.0.2.2"), Disabled: Bool(true)}}) {
+		t.Errorf("unexpected RRset %+v", rrSet)
+	}
+	if len(rrSet.Comments) != 1 {
+		t.Errorf("comments have not been preserved: %+v", rrSet.Comments)
+	}
+
+	if err := p.Records.RemoveRecord(context.Background(), testDomain, "www."+testDomain, RRTypeA, "192.0.2.2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(patches) != 2 {
+		t.Fatalf("unexpected patches %+v", patches)
+	}
+	rrSet = patches[1].Sets[0]
+	if *rrSet.ChangeType != ChangeTypeDelete || len(rrSet.Records) != 0 {
+		t.Errorf("the last record did not delete the RRset: %+v", rrSet)
+	}
+}
+
+func TestAddRecordConcurrentModification(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("concurrent writers are simulated by mocks")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	rrSets := generateTestRecordStore("example.com")
+	records := rrSets[0].Records
+	var patches []RRsets
+	registerRecordStoreMockResponder("example.com", &rrSets, &patches, func(getCount int) *http.Response {
+		if getCount > 1 {
+			rrSets[len(rrSets)-1].Records = records
+		}
+		return nil
+	})
+
+	p := initialisePowerDNSTestClient()
+	err := p.Records.AddRecord(context.Background(), "example.com", "www.example.com", RRTypeA, 60, "192.0.2.3")
+	if !errors.Is(err, ErrConcurrentModification) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(patches) != maxRecordUpdateAttempts {
+		t.Errorf("unexpected patches %+v", patches)
+	}
+}
+
+func TestAddRecordConcurrentInsert(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("concurrent writers are simulated by mocks")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	rrSets := generateTestRecordStore("example.com")
+	var patches []RRsets
+	registerRecordStoreMockResponder("example.com", &rrSets, &patches, func(getCount int) *http.Response {
+		// A concurrent writer inserts a record between the read and the write
+		if getCount == 2 {
+			rrSets[0].Records = append(generateTestRecordStore("example.com")[0].Records, Record{Content: String("198.51.100.1"), Disabled: Bool(false)})
+		}
+		return nil
+	})
+
+	p := initialisePowerDNSTestClient()
+	err := p.Records.AddRecord(context.Background(), "example.com", "www.example.com", RRTypeA, 60, "192.0.2.3")
+	if !errors.Is(err, ErrConcurrentModification) || err.Error() != "concurrent modification: www.example.com. A has been changed while it was updated" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(patches) != 0 || len(rrSets[0].Records) != 3 {
+		t.Errorf("the concurrent insert has been overwritten: %+v", rrSets[0].Records)
+	}
+}
+
+func TestAddRecordRetriesAfterConcurrentModification(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("concurrent writers are simulated by mocks")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	rrSets := generateTestRecordStore("example.com")
+	var patches []RRsets
+	registerRecordStoreMockResponder("example.com", &rrSets, &patches, func(getCount int) *http.Response {
+		// A concurrent writer replaces the RRset after the first write and drops the added record
+		if getCount == 3 {
+			rrSets[len(rrSets)-1].Records = append(generateTestRecordStore("example.com")[0].Records, Record{Content: String("198.51.100.1"), Disabled: Bool(false)})
+		}
+		return nil
+	})
+
+	p := initialisePowerDNSTestClient()
+	if err := p.Records.AddRecord(context.Background(), "example.com", "www.example.com", RRTypeA, 60, "192.0.2.3"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(patches) != 2 || len(patches[1].Sets[0].Records) != 4 {
+		t.Errorf("the update has not been applied again: %+v", patches)
+	}
+}
+
+func TestRemoveRecordRetriesAfterConcurrentModification(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("concurrent writers are simulated by mocks")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	rrSets := generateTestRecordStore("example.com")
+	var patches []RRsets
+	registerRecordStoreMockResponder("example.com", &rrSets, &patches, func(getCount int) *http.Response {
+		// A concurrent writer restores the removed record after the first write
+		if getCount == 3 {
+			rrSets[len(rrSets)-1].Records = generateTestRecordStore("example.com")[0].Records
+		}
+		return nil
+	})
+
+	p := initialisePowerDNSTestClient()
+	if err := p.Records.RemoveRecord(context.Background(), "example.com", "www.example.com", RRTypeA, "192.0.2.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(patches) != 2 || len(rrSets[len(rrSets)-1].Records) != 1 {
+		t.Errorf("the update has not been applied again: %+v", patches)
+	}
+}
+
+func TestUpdateRRSetErrors(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("failures are simulated by mocks")
+	}
+
+	for _, failingGet := range []int{1, 2, 3} {
+		t.Run(fmt.Sprintf("GET%d", failingGet), func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			rrSets := generateTestRecordStore("example.com")
+			var patches []RRsets
+			registerRecordStoreMockResponder("example.com", &rrSets, &patches, func(getCount int) *http.Response {
+				if getCount == failingGet {
+					return httpmock.NewStringResponse(http.StatusNotFound, "Not Found")
+				}
+				return nil
+			})
+
+			p := initialisePowerDNSTestClient()
+			if err := p.Records.RemoveRecord(context.Background(), "example.com", "www.example.com", RRTypeA, "192.0.2.1"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("PATCH", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		rrSets := generateTestRecordStore("example.com")
+		var patches []RRsets
+		registerRecordStoreMockResponder("example.com", &rrSets, &patches, func(int) *http.Response { return nil })
+		httpmock.RegisterResponder(http.MethodPatch, generateTestAPIVHostURL()+"/zones/example.com.", httpmock.NewStringResponder(http.StatusUnprocessableEntity, "Unprocessable Entity"))
+
+		p := initialisePowerDNSTestClient()
+		if err := p.Records.RemoveRecord(context.Background(), "example.com", "www.example.com", RRTypeA, "192.0.2.1"); !errors.Is(err, ErrValidation) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package powerdns
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy defines how transient failures are retried by the Client.
+//
+// Requests are retried on connection errors, "429 Too Many Requests" and 5xx responses (except "501 Not Implemented").
+// GET, PUT and DELETE requests are retried, PATCH requests only if RetryPatch is set.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the initial attempt.
+	MaxRetries int
+
+	// MinBackoff is the delay before the first retry. It doubles with every subsequent retry.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the exponential backoff, or 5 seconds if it is zero. A Retry-After header sent by the server takes precedence.
+	MaxBackoff time.Duration
+
+	// RetryPatch enables retries for PATCH requests.
+	// PowerDNS applies RRset changes atomically, but a retried PATCH may still be applied twice if the first response got lost.
+	RetryPatch bool
+}
+
+// defaultMaxBackoff caps the exponential backoff of a RetryPolicy without MaxBackoff
+const defaultMaxBackoff = 5 * time.Second
+
+// DefaultRetryPolicy is a reasonable RetryPolicy for most use cases.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 100 * time.Millisecond,
+	MaxBackoff: defaultMaxBackoff,
+}
+
+// WithRetryPolicy is an option for New to retry transient failures.
+func WithRetryPolicy(policy RetryPolicy) NewOption {
+	return func(client *Client) {
+		client.retryPolicy = &policy
+	}
+}
+
+func (r *RetryPolicy) allowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPatch:
+		return r.RetryPatch
+	default:
+		return false
+	}
+}
+
+func (r *RetryPolicy) backoff(retry int, resp *http.Response) time.Duration {
+	if delay, ok := parseRetryAfter(resp); ok {
+		return delay
+	}
+
+	maxBackoff := r.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	// Double the delay only as long as it stays below the cap, so that it cannot overflow.
+	delay := r.MinBackoff
+	for range retry {
+		if delay > maxBackoff/2 {
+			delay = maxBackoff
+			break
+		}
+		delay *= 2
+	}
+	delay = min(delay, maxBackoff)
+	if delay <= 0 {
+		return 0
+	}
+
+	// Equal jitter: Keep half of the delay and randomize the other half.
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+func isRetryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || (resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented)
+}
+
+func isRewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	rewoundReq := req.Clone(req.Context())
+	rewoundReq.Body = body
+	return rewoundReq, nil
+}
+
+func discardResponse(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (p *Client) send(req *http.Request) (*http.Response, error) {
+	doer := p.doer()
+
+	policy := p.retryPolicy
+	if policy == nil || !policy.allowsMethod(req.Method) || !isRewindable(req) {
+		return doer.Do(req)
+	}
+
+	for retry := 0; ; retry++ {
+		resp, err := doer.Do(req)
+		if retry >= policy.MaxRetries || !isRetryable(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		delay := policy.backoff(retry, resp)
+		discardResponse(resp)
+
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		if req, err = rewindRequest(req); err != nil {
+			return nil, err
+		}
+	}
+}
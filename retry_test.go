@@ -0,0 +1,248 @@
+package powerdns
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: time.Millisecond,
+	MaxBackoff: 2 * time.Millisecond,
+}
+
+func registerFlakyMockResponder(method string, failures int, failure httpmock.Responder) *[]string {
+	bodies := make([]string, 0)
+	httpmock.RegisterResponder(method, generateTestAPIVHostURL()+"/flaky",
+		func(req *http.Request) (*http.Response, error) {
+			body := ""
+			if req.Body != nil {
+				bodyBytes, _ := io.ReadAll(req.Body)
+				body = string(bodyBytes)
+			}
+			bodies = append(bodies, body)
+
+			if len(bodies) <= failures {
+				return failure(req)
+			}
+			return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+		},
+	)
+	return &bodies
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	p := &Client{}
+	WithRetryPolicy(DefaultRetryPolicy)(p)
+	if p.retryPolicy == nil || *p.retryPolicy != DefaultRetryPolicy {
+		t.Error("Unexpected retry policy")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("retries require injected failures")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	unavailable := httpmock.NewStringResponder(http.StatusServiceUnavailable, "Service Unavailable")
+	tooManyRequests := httpmock.NewStringResponder(http.StatusTooManyRequests, "Too Many Requests").HeaderSet(http.Header{"Retry-After": {"0"}})
+	connectionReset := httpmock.NewErrorResponder(errors.New("connection reset by peer"))
+	notImplemented := httpmock.NewStringResponder(http.StatusNotImplemented, "Not Implemented")
+
+	testCases := []struct {
+		desc         string
+		method       string
+		policy       *RetryPolicy
+		failures     int
+		failure      httpmock.Responder
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"GET recovers from 503", http.MethodGet, &testRetryPolicy, 2, unavailable, 3, false},
+		{"PUT recovers from 429 with Retry-After", http.MethodPut, &testRetryPolicy, 1, tooManyRequests, 2, false},
+		{"DELETE recovers from connection reset", http.MethodDelete, &testRetryPolicy, 1, connectionReset, 2, false},
+		{"GET gives up after max retries", http.MethodGet, &testRetryPolicy, 10, unavailable, 4, true},
+		{"GET does not retry 501", http.MethodGet, &testRetryPolicy, 1, notImplemented, 1, true},
+		{"PATCH is not retried by default", http.MethodPatch, &testRetryPolicy, 1, unavailable, 1, true},
+		{"PATCH is retried if enabled", http.MethodPatch, &RetryPolicy{MaxRetries: 1, RetryPatch: true}, 1, unavailable, 2, false},
+		{"POST is never retried", http.MethodPost, &RetryPolicy{MaxRetries: 1, RetryPatch: true}, 1, unavailable, 1, true},
+		{"No retries without policy", http.MethodGet, nil, 1, unavailable, 1, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			httpmock.Reset()
+			bodies := registerFlakyMockResponder(tc.method, tc.failures, tc.failure)
+
+			p := initialisePowerDNSTestClient()
+			p.retryPolicy = tc.policy
+			req, _ := p.newRequest(context.Background(), tc.method, "servers/localhost/flaky", nil, map[string]string{"foo": "bar"})
+			_, err := p.do(req, nil)
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if len(*bodies) != tc.wantAttempts {
+				t.Errorf("Expected %d attempts, got %d", tc.wantAttempts, len(*bodies))
+			}
+			for i, body := range *bodies {
+				if body != "{\"foo\":\"bar\"}\n" {
+					t.Errorf("Attempt %d sent unexpected body %q", i, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryContextCancellation(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("retries require injected failures")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	bodies := registerFlakyMockResponder(http.MethodGet, 10, httpmock.NewStringResponder(http.StatusServiceUnavailable, "Service Unavailable"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithRetryPolicy(RetryPolicy{MaxRetries: 10, MinBackoff: time.Hour}))
+	req, _ := p.newRequest(ctx, http.MethodGet, "servers/localhost/flaky", nil, nil)
+	if _, err := p.do(req, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(*bodies) != 1 {
+		t.Errorf("Expected 1 attempt, got %d", len(*bodies))
+	}
+}
+
+func TestRetryGetBodyError(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("retries require injected failures")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	bodies := registerFlakyMockResponder(http.MethodPut, 1, httpmock.NewStringResponder(http.StatusServiceUnavailable, "Service Unavailable"))
+
+	p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithRetryPolicy(testRetryPolicy))
+	req, _ := p.newRequest(context.Background(), http.MethodPut, "servers/localhost/flaky", nil, "foo")
+	req.GetBody = func() (io.ReadCloser, error) {
+		return nil, errors.New("body is gone")
+	}
+	if _, err := p.do(req, nil); err == nil || err.Error() != "body is gone" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(*bodies) != 1 {
+		t.Errorf("Expected 1 attempt, got %d", len(*bodies))
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		policy  RetryPolicy
+		retry   int
+		resp    *http.Response
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{"first retry", RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}, 0, nil, 50 * time.Millisecond, 100 * time.Millisecond},
+		{"third retry", RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}, 2, nil, 200 * time.Millisecond, 400 * time.Millisecond},
+		{"capped", RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}, 10, nil, 500 * time.Millisecond, time.Second},
+		{"overflow", RetryPolicy{MinBackoff: time.Second, MaxBackoff: time.Minute}, 62, nil, 30 * time.Second, time.Minute},
+		{"overflow without MaxBackoff", RetryPolicy{MinBackoff: time.Second}, 100, nil, 2500 * time.Millisecond, 5 * time.Second},
+		{"MinBackoff above default MaxBackoff", RetryPolicy{MinBackoff: time.Minute}, 0, nil, 2500 * time.Millisecond, 5 * time.Second},
+		{"zero", RetryPolicy{}, 3, nil, 0, 0},
+		{"Retry-After", RetryPolicy{MinBackoff: time.Millisecond}, 0, &http.Response{Header: http.Header{"Retry-After": {"7"}}}, 7 * time.Second, 7 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			delay := tc.policy.backoff(tc.retry, tc.resp)
+			if delay < tc.wantMin || delay > tc.wantMax {
+				t.Errorf("Backoff %s not within [%s, %s]", delay, tc.wantMin, tc.wantMax)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		header    string
+		wantDelay time.Duration
+		wantOK    bool
+	}{
+		{"missing", "", 0, false},
+		{"seconds", "120", 2 * time.Minute, true},
+		{"negative", "-1", 0, false},
+		{"past date", "Wed, 21 Oct 2015 07:28:00 GMT", 0, true},
+		{"garbage", "soon", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tc.header != "" {
+				resp.Header.Set("Retry-After", tc.header)
+			}
+			delay, ok := parseRetryAfter(resp)
+			if delay != tc.wantDelay || ok != tc.wantOK {
+				t.Errorf("Unexpected result %s, %t", delay, ok)
+			}
+		})
+	}
+
+	t.Run("future date", func(t *testing.T) {
+		resp := &http.Response{Header: http.Header{"Retry-After": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}}}
+		if delay, ok := parseRetryAfter(resp); !ok || delay < 59*time.Minute || delay > time.Hour {
+			t.Errorf("Unexpected result %s, %t", delay, ok)
+		}
+	})
+
+	t.Run("nil response", func(t *testing.T) {
+		if _, ok := parseRetryAfter(nil); ok {
+			t.Error("Unexpected result for nil response")
+		}
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := []struct {
+		desc string
+		ctx  context.Context
+		resp *http.Response
+		err  error
+		want bool
+	}{
+		{"connection error", context.Background(), nil, errors.New("connection refused"), true},
+		{"canceled context", canceledCtx, nil, context.Canceled, false},
+		{"bad gateway", context.Background(), &http.Response{StatusCode: http.StatusBadGateway}, nil, true},
+		{"unprocessable entity", context.Background(), &http.Response{StatusCode: http.StatusUnprocessableEntity}, nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if isRetryable(tc.ctx, tc.resp, tc.err) != tc.want {
+				t.Errorf("isRetryable returned %t", !tc.want)
+			}
+		})
+	}
+}
+
+func TestRewindRequestWithoutBody(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, testBaseURL, nil)
+	if rewoundReq, err := rewindRequest(req); err != nil || rewoundReq != req {
+		t.Error("Request without body should be reused")
+	}
+}
@@ -0,0 +1,255 @@
+package powerdns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RolloverPhase represents a string-valued phase of a key rollover
+type RolloverPhase string
+
+const (
+	// RolloverPhaseIdle represents a zone without a rollover in progress
+	RolloverPhaseIdle RolloverPhase = ""
+	// RolloverPhaseStarted represents a rollover whose new key has not been created yet
+	RolloverPhaseStarted RolloverPhase = "started"
+	// RolloverPhasePublished represents a rollover whose new key is published, but not used yet
+	RolloverPhasePublished RolloverPhase = "published"
+	// RolloverPhaseActive represents a rollover whose new key is used, while the old key is still published
+	RolloverPhaseActive RolloverPhase = "active"
+	// RolloverPhaseCompleted represents a rollover whose old key has been removed
+	RolloverPhaseCompleted RolloverPhase = "completed"
+)
+
+// RolloverState is the persisted progress of a key rollover
+type RolloverState struct {
+	Phase    RolloverPhase `json:"phase"`
+	OldKeyID uint64        `json:"old_key_id"`
+	NewKeyID uint64        `json:"new_key_id,omitempty"`
+	// Due is the time from which on Step advances to the next phase
+	Due time.Time `json:"due"`
+}
+
+// RolloverPolicy configures a Rollover.
+type RolloverPolicy struct {
+	// KeyType selects the rolled key and the method: "zsk" for a pre-publish rollover and "ksk" for a double-signature rollover
+	KeyType string
+	// Algorithm of the new key, which defaults to the algorithm of the old key
+	Algorithm string
+	// Bits of the new key, which defaults to the size of the old key
+	Bits uint64
+	// PublishDelay is the time between publishing the new key and using it, at least the DNSKEY TTL plus the propagation delay
+	PublishDelay time.Duration
+	// RetireDelay is the time between using the new key and removing the old key.
+	// For a ZSK, it should cover the maximum TTL of the signed RRsets, for a KSK the DS TTL of the parent zone after the DS records have been replaced.
+	RetireDelay time.Duration
+	// PublishDS is called once the new KSK signs, so that the DS records at the parent can be replaced before RetireDelay elapses.
+	// Step fails and retries later if it returns an error.
+	PublishDS func(ctx context.Context, zone string, cryptokey *Cryptokey) error
+}
+
+// Rollover replaces the key of a zone step by step.
+// Its state is stored in the metadata kind X-GO-POWERDNS-ROLLOVER-<KeyType> of the zone, so that an interrupted rollover can be resumed by any process.
+type Rollover struct {
+	service *CryptokeysService
+	policy  RolloverPolicy
+}
+
+// NewRollover returns a Rollover for the given policy
+func (c *CryptokeysService) NewRollover(policy RolloverPolicy) *Rollover {
+	policy.KeyType = strings.ToLower(policy.KeyType)
+	return &Rollover{service: c, policy: policy}
+}
+
+func (r *Rollover) metadataKind() MetadataKind {
+	return MetadataKind("X-GO-POWERDNS-ROLLOVER-" + strings.ToUpper(r.policy.KeyType))
+}
+
+// State returns the progress of the rollover in zone
+func (r *Rollover) State(ctx context.Context, zone string) (*RolloverState, error) {
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "RolloverState", Zone: makeDomainCanonical(zone)})
+
+	metadata, err := r.service.client.Metadata.Get(ctx, zone, r.metadataKind())
+	if err != nil {
+		return nil, err
+	}
+
+	state := &RolloverState{}
+	if len(metadata.Metadata) == 0 {
+		return state, nil
+	}
+	if err := json.Unmarshal([]byte(metadata.Metadata[0]), state); err != nil {
+		return nil, fmt.Errorf("invalid rollover state %s: %w", r.metadataKind(), err)
+	}
+	return state, nil
+}
+
+// Start begins to replace the only key of the policy's type in zone and makes as much progress as possible at now.
+func (r *Rollover) Start(ctx context.Context, zone string, now time.Time) (*RolloverState, error) {
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "RolloverStart", Zone: makeDomainCanonical(zone)})
+
+	if r.policy.KeyType != "zsk" && r.policy.KeyType != "ksk" {
+		return nil, fmt.Errorf("unsupported rollover key type %q", r.policy.KeyType)
+	}
+
+	state, err := r.State(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	if state.Phase != RolloverPhaseIdle {
+		return nil, fmt.Errorf("%w: %s rollover of %s is %s", ErrRolloverInProgress, r.policy.KeyType, zone, state.Phase)
+	}
+
+	keys, err := r.keys(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) != 1 || !BoolValue(keys[0].Active) {
+		return nil, fmt.Errorf("%s rollover requires exactly one active %s, found %d keys", r.policy.KeyType, r.policy.KeyType, len(keys))
+	}
+
+	state = &RolloverState{Phase: RolloverPhaseStarted, OldKeyID: *keys[0].ID, Due: now}
+	if err := r.save(ctx, zone, state); err != nil {
+		return nil, err
+	}
+	return r.Step(ctx, zone, now)
+}
+
+// Step advances the rollover in zone through all phases which are due at now and returns the resulting state.
+// A zone without a rollover in progress is left untouched.
+func (r *Rollover) Step(ctx context.Context, zone string, now time.Time) (*RolloverState, error) {
+	ctx = withOperation(ctx, Operation{Service: "Cryptokeys", Method: "RolloverStep", Zone: makeDomainCanonical(zone)})
+
+	state, err := r.State(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	for state.Phase != RolloverPhaseIdle && state.Phase != RolloverPhaseCompleted && !now.Before(state.Due) {
+		if err := r.advance(ctx, zone, state, now); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+// advance performs the actions of the next phase and persists it. The actions are idempotent, so they can be repeated if persisting fails.
+func (r *Rollover) advance(ctx context.Context, zone string, state *RolloverState, now time.Time) error {
+	switch state.Phase {
+	case RolloverPhaseStarted:
+		newKeyID, err := r.createKey(ctx, zone, state.OldKeyID)
+		if err != nil {
+			return err
+		}
+		state.Phase, state.NewKeyID, state.Due = RolloverPhasePublished, newKeyID, now.Add(r.policy.PublishDelay)
+
+	case RolloverPhasePublished:
+		if err := r.activate(ctx, zone, state); err != nil {
+			return err
+		}
+		state.Phase, state.Due = RolloverPhaseActive, now.Add(r.policy.RetireDelay)
+
+	case RolloverPhaseActive:
+		if err := r.service.Delete(ctx, zone, state.OldKeyID); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		state.Phase, state.Due = RolloverPhaseCompleted, now
+		return r.service.client.Metadata.Delete(ctx, zone, r.metadataKind())
+
+	default:
+		return fmt.Errorf("unknown rollover phase %q", state.Phase)
+	}
+
+	return r.save(ctx, zone, state)
+}
+
+// createKey creates the new key, published and, for a double-signature rollover, active, and returns the ID reported for it.
+// Since Start requires the old key to be the only one, another key has been created by a step whose state could not be saved, and is adopted instead of creating another one.
+// Several other keys cannot be told apart, so an error is returned in this case.
+func (r *Rollover) createKey(ctx context.Context, zone string, oldKeyID uint64) (uint64, error) {
+	keys, err := r.keys(ctx, zone)
+	if err != nil {
+		return 0, err
+	}
+
+	i := slices.IndexFunc(keys, func(key Cryptokey) bool {
+		return *key.ID == oldKeyID
+	})
+	if i < 0 {
+		return 0, fmt.Errorf("old %s %d of %s does not exist anymore", r.policy.KeyType, oldKeyID, zone)
+	}
+	oldKey := keys[i]
+	switch newKeys := slices.Delete(keys, i, i+1); len(newKeys) {
+	case 0:
+	case 1:
+		return *newKeys[0].ID, nil
+	default:
+		return 0, fmt.Errorf("new %s of %s is ambiguous, since %d keys have been added besides %d", r.policy.KeyType, zone, len(newKeys), oldKeyID)
+	}
+
+	cryptokey := &Cryptokey{
+		KeyType:   String(r.policy.KeyType),
+		Active:    Bool(r.policy.KeyType == "ksk"),
+		Published: Bool(true),
+		Algorithm: oldKey.Algorithm,
+		Bits:      oldKey.Bits,
+	}
+	if r.policy.Algorithm != "" {
+		cryptokey.Algorithm = String(r.policy.Algorithm)
+	}
+	if r.policy.Bits != 0 {
+		cryptokey.Bits = Uint64(r.policy.Bits)
+	}
+
+	created, err := r.service.Create(ctx, zone, cryptokey)
+	if err != nil {
+		return 0, err
+	}
+	return *created.ID, nil
+}
+
+// activate makes the new key sign the zone. A new ZSK replaces the old one, whereas a new KSK has been signing since its creation and its DS records are published.
+func (r *Rollover) activate(ctx context.Context, zone string, state *RolloverState) error {
+	if r.policy.KeyType == "ksk" {
+		if r.policy.PublishDS == nil {
+			return nil
+		}
+		cryptokey, err := r.service.Get(ctx, zone, state.NewKeyID)
+		if err != nil {
+			return err
+		}
+		return r.policy.PublishDS(ctx, makeDomainCanonical(zone), cryptokey)
+	}
+
+	if err := r.service.Activate(ctx, zone, state.NewKeyID); err != nil {
+		return err
+	}
+	return r.service.Deactivate(ctx, zone, state.OldKeyID)
+}
+
+// keys returns the keys of the policy's type
+func (r *Rollover) keys(ctx context.Context, zone string) ([]Cryptokey, error) {
+	cryptokeys, err := r.service.List(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]Cryptokey, 0, len(cryptokeys))
+	for _, cryptokey := range cryptokeys {
+		if strings.EqualFold(StringValue(cryptokey.KeyType), r.policy.KeyType) {
+			keys = append(keys, cryptokey)
+		}
+	}
+	return keys, nil
+}
+
+func (r *Rollover) save(ctx context.Context, zone string, state *RolloverState) error {
+	data, _ := json.Marshal(state)
+	_, err := r.service.client.Metadata.Set(ctx, zone, r.metadataKind(), []string{string(data)})
+	return err
+}
@@ -0,0 +1,308 @@
+package powerdns_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joeig/go-powerdns/v3"
+	"github.com/joeig/go-powerdns/v3/powerdnstest"
+)
+
+var rolloverStartTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func newRolloverTestServer(t *testing.T, keyTypes ...string) (*powerdnstest.Server, *powerdns.Client) {
+	t.Helper()
+
+	s := powerdnstest.NewServer()
+	t.Cleanup(s.Close)
+	client := s.Client(powerdns.WithRetryPolicy(powerdns.RetryPolicy{}))
+
+	if err := s.AddZone(powerdns.Zone{Name: powerdns.String("example.com."), Kind: powerdns.ZoneKindPtr(powerdns.NativeZoneKind)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, keyType := range keyTypes {
+		if _, err := client.Cryptokeys.Create(context.Background(), "example.com", &powerdns.Cryptokey{KeyType: powerdns.String(keyType), Active: powerdns.Bool(true), Algorithm: powerdns.String("ecdsa256")}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	return s, client
+}
+
+// rolloverKeys returns the keys of the zone by ID
+func rolloverKeys(t *testing.T, client *powerdns.Client) map[uint64]powerdns.Cryptokey {
+	t.Helper()
+
+	cryptokeys, err := client.Cryptokeys.List(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys := make(map[uint64]powerdns.Cryptokey, len(cryptokeys))
+	for _, cryptokey := range cryptokeys {
+		keys[*cryptokey.ID] = cryptokey
+	}
+	return keys
+}
+
+func assertRolloverPhase(t *testing.T, state *powerdns.RolloverState, err error, phase powerdns.RolloverPhase) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Phase != phase {
+		t.Fatalf("unexpected phase %q instead of %q", state.Phase, phase)
+	}
+}
+
+func TestZSKRollover(t *testing.T) {
+	_, client := newRolloverTestServer(t, "ksk", "zsk")
+	ctx := context.Background()
+	rollover := client.Cryptokeys.NewRollover(powerdns.RolloverPolicy{KeyType: "ZSK", Bits: 256, PublishDelay: time.Hour, RetireDelay: 24 * time.Hour})
+
+	state, err := rollover.Start(ctx, "example.com", rolloverStartTime)
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhasePublished)
+	if state.OldKeyID != 2 || state.NewKeyID != 3 || !state.Due.Equal(rolloverStartTime.Add(time.Hour)) {
+		t.Errorf("unexpected state %+v", state)
+	}
+	keys := rolloverKeys(t, client)
+	if newKey := keys[3]; *newKey.KeyType != "zsk" || *newKey.Active || !*newKey.Published || *newKey.Algorithm != "ECDSAP256SHA256" {
+		t.Errorf("unexpected new key %+v", newKey)
+	}
+
+	if _, err := rollover.Start(ctx, "example.com", rolloverStartTime); !errors.Is(err, powerdns.ErrRolloverInProgress) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	state, err = rollover.Step(ctx, "example.com", rolloverStartTime.Add(59*time.Minute))
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhasePublished)
+
+	state, err = rollover.Step(ctx, "example.com", rolloverStartTime.Add(time.Hour))
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhaseActive)
+	keys = rolloverKeys(t, client)
+	if !*keys[3].Active || *keys[2].Active || !*keys[2].Published || !*keys[1].Active {
+		t.Errorf("unexpected keys %+v", keys)
+	}
+
+	// A new process resumes the rollover from the zone metadata
+	rollover = client.Cryptokeys.NewRollover(powerdns.RolloverPolicy{KeyType: "zsk", PublishDelay: time.Hour, RetireDelay: 24 * time.Hour})
+	state, err = rollover.Step(ctx, "example.com", rolloverStartTime.Add(25*time.Hour))
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhaseCompleted)
+	if keys = rolloverKeys(t, client); len(keys) != 2 {
+		t.Errorf("unexpected keys %+v", keys)
+	}
+
+	state, err = rollover.State(ctx, "example.com")
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhaseIdle)
+	state, err = rollover.Step(ctx, "example.com", rolloverStartTime.Add(48*time.Hour))
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhaseIdle)
+}
+
+func TestKSKRollover(t *testing.T) {
+	_, client := newRolloverTestServer(t, "ksk", "zsk")
+	ctx := context.Background()
+
+	var published []*powerdns.Cryptokey
+	publishErr := errors.New("parent zone is not reachable")
+	rollover := client.Cryptokeys.NewRollover(powerdns.RolloverPolicy{
+		KeyType:      "ksk",
+		Algorithm:    "ed25519",
+		PublishDelay: time.Hour,
+		RetireDelay:  48 * time.Hour,
+		PublishDS: func(ctx context.Context, zone string, cryptokey *powerdns.Cryptokey) error {
+			if zone != "example.com." {
+				t.Errorf("unexpected zone %s", zone)
+			}
+			published = append(published, cryptokey)
+			return publishErr
+		},
+	})
+
+	state, err := rollover.Start(ctx, "example.com", rolloverStartTime)
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhasePublished)
+	keys := rolloverKeys(t, client)
+	if newKey := keys[3]; *newKey.KeyType != "ksk" || !*newKey.Active || !*newKey.Published || *newKey.Algorithm != "ED25519" || !*keys[1].Active {
+		t.Errorf("unexpected keys %+v", keys)
+	}
+
+	if _, err := rollover.Step(ctx, "example.com", rolloverStartTime.Add(time.Hour)); !errors.Is(err, publishErr) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	state, err = rollover.State(ctx, "example.com")
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhasePublished)
+
+	publishErr = nil
+	state, err = rollover.Step(ctx, "example.com", rolloverStartTime.Add(2*time.Hour))
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhaseActive)
+	if len(published) != 2 || *published[1].ID != 3 || len(published[1].DS) == 0 || !state.Due.Equal(rolloverStartTime.Add(50*time.Hour)) {
+		t.Errorf("unexpected published keys %+v, state %+v", published, state)
+	}
+
+	state, err = rollover.Step(ctx, "example.com", rolloverStartTime.Add(50*time.Hour))
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhaseCompleted)
+	if keys := rolloverKeys(t, client); len(keys) != 2 || keys[1].ID != nil {
+		t.Errorf("unexpected keys %+v", keys)
+	}
+}
+
+func TestKSKRolloverWithoutPublishDS(t *testing.T) {
+	_, client := newRolloverTestServer(t, "ksk")
+	ctx := context.Background()
+	rollover := client.Cryptokeys.NewRollover(powerdns.RolloverPolicy{KeyType: "ksk"})
+
+	state, err := rollover.Start(ctx, "example.com", rolloverStartTime)
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhaseCompleted)
+	if keys := rolloverKeys(t, client); len(keys) != 1 || keys[2].ID == nil {
+		t.Errorf("unexpected keys %+v", keys)
+	}
+}
+
+func TestRolloverResume(t *testing.T) {
+	_, client := newRolloverTestServer(t, "zsk")
+	ctx := context.Background()
+	rollover := client.Cryptokeys.NewRollover(powerdns.RolloverPolicy{KeyType: "zsk", PublishDelay: time.Hour})
+	kind := powerdns.MetadataKind("X-GO-POWERDNS-ROLLOVER-ZSK")
+
+	// The new key has been created, but the state has not been saved anymore
+	if _, err := client.Metadata.Set(ctx, "example.com", kind, []string{`{"phase": "started", "old_key_id": 1, "due": "2024-01-02T03:04:05Z"}`}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Cryptokeys.Create(ctx, "example.com", &powerdns.Cryptokey{KeyType: powerdns.String("zsk")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state, err := rollover.Step(ctx, "example.com", rolloverStartTime)
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhasePublished)
+	if state.NewKeyID != 2 || len(rolloverKeys(t, client)) != 2 {
+		t.Errorf("unexpected state %+v", state)
+	}
+
+	// The old key has been deleted, but the state has not been removed anymore
+	if _, err := client.Metadata.Set(ctx, "example.com", kind, []string{`{"phase": "active", "old_key_id": 7, "new_key_id": 2, "due": "2024-01-02T03:04:05Z"}`}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state, err = rollover.Step(ctx, "example.com", rolloverStartTime)
+	assertRolloverPhase(t, state, err, powerdns.RolloverPhaseCompleted)
+}
+
+func TestRolloverResumeAmbiguous(t *testing.T) {
+	_, client := newRolloverTestServer(t, "zsk", "ksk")
+	ctx := context.Background()
+	rollover := client.Cryptokeys.NewRollover(powerdns.RolloverPolicy{KeyType: "zsk"})
+
+	// Another key has been added besides the one created by an interrupted step
+	if _, err := client.Metadata.Set(ctx, "example.com", "X-GO-POWERDNS-ROLLOVER-ZSK", []string{`{"phase": "started", "old_key_id": 1, "due": "2024-01-02T03:04:05Z"}`}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range 2 {
+		if _, err := client.Cryptokeys.Create(ctx, "example.com", &powerdns.Cryptokey{KeyType: powerdns.String("zsk")}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := rollover.Step(ctx, "example.com", rolloverStartTime); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if state, err := rollover.State(ctx, "example.com"); err != nil || state.NewKeyID != 0 || len(rolloverKeys(t, client)) != 4 {
+		t.Errorf("unexpected state %+v: %v", state, err)
+	}
+}
+
+func TestRolloverErrors(t *testing.T) {
+	_, client := newRolloverTestServer(t, "zsk", "zsk")
+	ctx := context.Background()
+	rollover := client.Cryptokeys.NewRollover(powerdns.RolloverPolicy{KeyType: "zsk"})
+	kind := powerdns.MetadataKind("X-GO-POWERDNS-ROLLOVER-ZSK")
+
+	if _, err := client.Cryptokeys.NewRollover(powerdns.RolloverPolicy{KeyType: "csk"}).Start(ctx, "example.com", rolloverStartTime); err == nil {
+		t.Error("error is nil")
+	}
+	if _, err := rollover.Start(ctx, "example.org", rolloverStartTime); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := rollover.Step(ctx, "example.org", rolloverStartTime); !errors.Is(err, powerdns.ErrZoneNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := rollover.Start(ctx, "example.com", rolloverStartTime); err == nil {
+		t.Error("a rollover has been started with two keys")
+	}
+
+	testCases := []struct {
+		name  string
+		state string
+	}{
+		{"invalid", `{`},
+		{"unknown phase", `{"phase": "unknown"}`},
+		{"missing old key", `{"phase": "started", "old_key_id": 7}`},
+		{"missing new key", `{"phase": "published", "old_key_id": 1, "new_key_id": 7}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := client.Metadata.Set(ctx, "example.com", kind, []string{tc.state}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := client.Cryptokeys.Delete(ctx, "example.com", 2); err != nil && !errors.Is(err, powerdns.ErrNotFound) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := rollover.Step(ctx, "example.com", rolloverStartTime); err == nil {
+				t.Error("error is nil")
+			}
+		})
+	}
+}
+
+// failingMiddleware fails the requests with method and a path ending with pathSuffix, except for the first skip ones.
+func failingMiddleware(method, pathSuffix string, skip int) powerdns.Middleware {
+	return func(next powerdns.Doer) powerdns.Doer {
+		return powerdns.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method == method && strings.HasSuffix(req.URL.Path, pathSuffix) {
+				if skip == 0 {
+					return nil, errors.New("injected failure")
+				}
+				skip--
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+func TestRolloverRequestErrors(t *testing.T) {
+	testCases := []struct {
+		keyType, method, pathSuffix string
+		skip                        int
+	}{
+		{"zsk", http.MethodGet, "/cryptokeys", 0},
+		{"zsk", http.MethodGet, "/cryptokeys", 1},
+		{"zsk", http.MethodPost, "/cryptokeys", 0},
+		{"zsk", http.MethodPut, "/metadata/X-GO-POWERDNS-ROLLOVER-ZSK", 0},
+		{"zsk", http.MethodPut, "/metadata/X-GO-POWERDNS-ROLLOVER-ZSK", 1},
+		{"zsk", http.MethodPut, "/cryptokeys/3", 0},
+		{"zsk", http.MethodPut, "/cryptokeys/2", 0},
+		{"zsk", http.MethodDelete, "/cryptokeys/2", 0},
+		{"zsk", http.MethodDelete, "/metadata/X-GO-POWERDNS-ROLLOVER-ZSK", 0},
+		{"ksk", http.MethodGet, "/cryptokeys/3", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+tc.pathSuffix, func(t *testing.T) {
+			s, _ := newRolloverTestServer(t, "ksk", "zsk")
+			client := s.Client(powerdns.WithRetryPolicy(powerdns.RetryPolicy{}), powerdns.WithMiddleware(failingMiddleware(tc.method, tc.pathSuffix, tc.skip)))
+			rollover := client.Cryptokeys.NewRollover(powerdns.RolloverPolicy{
+				KeyType: tc.keyType,
+				PublishDS: func(context.Context, string, *powerdns.Cryptokey) error {
+					return nil
+				},
+			})
+
+			if _, err := rollover.Start(context.Background(), "example.com", rolloverStartTime); err == nil {
+				t.Error("error is nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package powerdns
+
+import (
+	"context"
+	"time"
+)
+
+// Snapshot is the difference between two consecutive samples of the statistics of a server.
+// Deltas and rates are meaningful for counters only, gauges should be read from Values.
+type Snapshot struct {
+	// Time is the time at which the current sample has been taken
+	Time time.Time
+	// Interval is the time since the previous sample, which is zero for the first sample
+	Interval time.Duration
+	// Restarted reports whether the server has been restarted since the previous sample, which is detected by a decreasing uptime
+	Restarted bool
+	// Values are the current values of all StatisticItems
+	Values map[string]uint64
+	// Deltas are the increases of the values since the previous sample.
+	// A value which has been reset, e.g. by a restart, increased by its current value.
+	Deltas map[string]uint64
+	// Rates are the deltas per second
+	Rates map[string]float64
+	// Err is set if the statistics could not be retrieved, in which case the other fields are not set.
+	// The next snapshot is compared with the last successful sample then.
+	Err error
+}
+
+// Rate returns the sum of the rates of the named statistics, e.g. of udp-queries and tcp-queries for the queries per second
+func (s *Snapshot) Rate(names ...string) float64 {
+	var rate float64
+	for _, name := range names {
+		rate += s.Rates[name]
+	}
+	return rate
+}
+
+// Ratio returns the share of the delta of hits in the sum of the deltas of hits and misses, e.g. the cache hit ratio.
+// It returns 0 if both have not increased.
+func (s *Snapshot) Ratio(hits, misses string) float64 {
+	total := s.Deltas[hits] + s.Deltas[misses]
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Deltas[hits]) / float64(total)
+}
+
+// Sampler polls the statistics of a server and computes the changes between consecutive samples.
+// It is not safe for concurrent use.
+type Sampler struct {
+	service  *StatisticsService
+	interval time.Duration
+	now      func() time.Time
+	previous *Snapshot
+}
+
+// SamplerOption is a functional option for StatisticsService.NewSampler.
+type SamplerOption func(*Sampler)
+
+// WithSamplerClock sets the clock which is used to timestamp the samples and to compute the rates.
+func WithSamplerClock(now func() time.Time) SamplerOption {
+	return func(s *Sampler) {
+		s.now = now
+	}
+}
+
+// NewSampler returns a Sampler which polls the statistics on interval when it is run
+func (s *StatisticsService) NewSampler(interval time.Duration, options ...SamplerOption) *Sampler {
+	sampler := &Sampler{service: s, interval: interval, now: time.Now}
+	for _, option := range options {
+		option(sampler)
+	}
+	return sampler
+}
+
+// Sample retrieves the statistics and compares them with the previous successful sample.
+// The snapshot of the first sample contains only the values.
+func (s *Sampler) Sample(ctx context.Context) Snapshot {
+	ctx = withOperation(ctx, Operation{Service: "Statistics", Method: "Sample"})
+
+	statistics, err := s.service.ListTyped(ctx, WithoutRings())
+	if err != nil {
+		return Snapshot{Time: s.now(), Err: err}
+	}
+
+	snapshot := Snapshot{Time: s.now(), Values: make(map[string]uint64)}
+	for _, statistic := range statistics {
+		if item, ok := statistic.(StatisticItem); ok {
+			snapshot.Values[item.Name] = item.Value
+		}
+	}
+
+	if previous := s.previous; previous != nil {
+		snapshot.Interval = snapshot.Time.Sub(previous.Time)
+		snapshot.Restarted = snapshot.Values["uptime"] < previous.Values["uptime"]
+		snapshot.Deltas = make(map[string]uint64, len(snapshot.Values))
+		snapshot.Rates = make(map[string]float64, len(snapshot.Values))
+		for name, value := range snapshot.Values {
+			delta := value
+			if previousValue, ok := previous.Values[name]; ok && !snapshot.Restarted && value >= previousValue {
+				delta = value - previousValue
+			}
+			snapshot.Deltas[name] = delta
+			if snapshot.Interval > 0 {
+				snapshot.Rates[name] = float64(delta) / snapshot.Interval.Seconds()
+			}
+		}
+	}
+
+	s.previous = &snapshot
+	return snapshot
+}
+
+// Run takes a sample immediately and on every interval, and publishes the snapshots to snapshots until ctx is done.
+// A snapshot without deltas, i.e. of the first successful sample, only serves as base for the rates and is not published.
+// It returns the error of ctx.
+func (s *Sampler) Run(ctx context.Context, snapshots chan<- Snapshot) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if snapshot := s.Sample(ctx); snapshot.Err != nil || snapshot.Deltas != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case snapshots <- snapshot:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package powerdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// registerSamplerMockResponder responds with the next of samples, each being uptime, udp-queries, query-cache-hit and query-cache-miss, or an error for nil
+func registerSamplerMockResponder(samples ...[]uint64) {
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/statistics",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+			if req.URL.Query().Get("includerings") != "false" || len(samples) == 0 {
+				return httpmock.NewStringResponse(http.StatusUnprocessableEntity, "Unprocessable Entity"), nil
+			}
+
+			sample := samples[0]
+			samples = samples[1:]
+			if sample == nil {
+				return httpmock.NewStringResponse(http.StatusInternalServerError, "Internal Server Error"), nil
+			}
+			return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`[
+				{"name": "uptime", "type": "StatisticItem", "value": "%d"},
+				{"name": "udp-queries", "type": "StatisticItem", "value": "%d"},
+				{"name": "query-cache-hit", "type": "StatisticItem", "value": "%d"},
+				{"name": "query-cache-miss", "type": "StatisticItem", "value": "%d"},
+				{"name": "response-by-qtype", "type": "MapStatisticItem", "value": [{"name": "A", "value": "1"}]}
+			]`, sample[0], sample[1], sample[2], sample[3])), nil
+		},
+	)
+}
+
+func newTestClock(step time.Duration) func() time.Time {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+func TestSamplerSample(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("statistics samples are served by mocks")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerSamplerMockResponder(
+		[]uint64{100, 1000, 50, 50},
+		[]uint64{110, 1200, 80, 70},
+		nil,
+		[]uint64{130, 1100, 100, 90},
+		[]uint64{5, 20, 1, 2},
+	)
+
+	p := initialisePowerDNSTestClient()
+	sampler := p.Statistics.NewSampler(time.Second, WithSamplerClock(newTestClock(10*time.Second)))
+	ctx := context.Background()
+
+	first := sampler.Sample(ctx)
+	if first.Err != nil || first.Values["udp-queries"] != 1000 || len(first.Values) != 4 || first.Deltas != nil || first.Rates != nil || first.Interval != 0 {
+		t.Errorf("unexpected first snapshot %+v", first)
+	}
+
+	second := sampler.Sample(ctx)
+	if second.Err != nil || second.Interval != 10*time.Second || second.Restarted || second.Deltas["udp-queries"] != 200 || second.Rate("udp-queries", "tcp-queries") != 20 || second.Ratio("query-cache-hit", "query-cache-miss") != 0.6 {
+		t.Errorf("unexpected second snapshot %+v", second)
+	}
+
+	if failed := sampler.Sample(ctx); failed.Err == nil || failed.Values != nil || failed.Time.IsZero() {
+		t.Errorf("unexpected failed snapshot %+v", failed)
+	}
+
+	reset := sampler.Sample(ctx)
+	wantDeltas := map[string]uint64{"uptime": 20, "udp-queries": 1100, "query-cache-hit": 20, "query-cache-miss": 20}
+	if reset.Err != nil || reset.Interval != 20*time.Second || reset.Restarted || !maps.Equal(reset.Deltas, wantDeltas) || reset.Rates["udp-queries"] != 55 {
+		t.Errorf("unexpected snapshot after counter reset %+v", reset)
+	}
+
+	restart := sampler.Sample(ctx)
+	wantDeltas = map[string]uint64{"uptime": 5, "udp-queries": 20, "query-cache-hit": 1, "query-cache-miss": 2}
+	if restart.Err != nil || !restart.Restarted || !maps.Equal(restart.Deltas, wantDeltas) {
+		t.Errorf("unexpected snapshot after restart %+v", restart)
+	}
+}
+
+func TestSamplerSampleWithoutInterval(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerSamplerMockResponder([]uint64{100, 1000, 0, 0}, []uint64{100, 1000, 0, 0})
+
+	p := initialisePowerDNSTestClient()
+	sampler := p.Statistics.NewSampler(time.Second, WithSamplerClock(newTestClock(0)))
+	sampler.Sample(context.Background())
+
+	snapshot := sampler.Sample(context.Background())
+	if snapshot.Err != nil || snapshot.Deltas["udp-queries"] != 0 || len(snapshot.Rates) != 0 || snapshot.Ratio("query-cache-hit", "query-cache-miss") != 0 {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+}
+
+func TestSamplerRun(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("statistics samples are served by mocks")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerSamplerMockResponder([]uint64{100, 1000, 0, 0}, nil, []uint64{101, 1010, 0, 0})
+
+	p := initialisePowerDNSTestClient()
+	sampler := p.Statistics.NewSampler(time.Millisecond, WithSamplerClock(newTestClock(time.Second)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	snapshots := make(chan Snapshot)
+	done := make(chan error)
+	go func() {
+		done <- sampler.Run(ctx, snapshots)
+	}()
+
+	if snapshot := <-snapshots; snapshot.Err == nil {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+	if snapshot := <-snapshots; snapshot.Err != nil || snapshot.Rates["udp-queries"] != 5 {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+
+	// Further samples fail, since no samples are left, and are not received anymore
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSamplerRunWithoutSnapshots(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerSamplerMockResponder([]uint64{100, 1000, 0, 0})
+
+	p := initialisePowerDNSTestClient()
+	sampler := p.Statistics.NewSampler(time.Hour)
+
+	// The first run only takes the base sample, the second run fails to publish its failed sample
+	for range 2 {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		if err := sampler.Run(ctx, make(chan Snapshot)); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		cancel()
+	}
+}
@@ -42,6 +42,8 @@ const (
 // The max parameter limits the number of returned results.
 // The objectType parameter filters results by type (all, zone, record, comment).
 func (s *SearchService) Data(ctx context.Context, query string, max int, objectType SearchObjectType) ([]SearchResult, error) {
+	ctx = withOperation(ctx, Operation{Service: "Search", Method: "Data"})
+
 	q := url.Values{}
 	q.Add("q", query)
 	q.Add("max", strconv.Itoa(max))
@@ -29,6 +29,8 @@ type CacheFlushResult struct {
 
 // List retrieves a list of Servers
 func (s *ServersService) List(ctx context.Context) ([]Server, error) {
+	ctx = withOperation(ctx, Operation{Service: "Servers", Method: "List"})
+
 	req, err := s.client.newRequest(ctx, http.MethodGet, "servers", nil, nil)
 	if err != nil {
 		return nil, err
@@ -41,6 +43,8 @@ func (s *ServersService) List(ctx context.Context) ([]Server, error) {
 
 // Get returns a certain Server
 func (s *ServersService) Get(ctx context.Context, vHost string) (*Server, error) {
+	ctx = withOperation(ctx, Operation{Service: "Servers", Method: "Get"})
+
 	req, err := s.client.newRequest(ctx, http.MethodGet, path.Join("servers", vHost), nil, nil)
 	if err != nil {
 		return nil, err
@@ -53,6 +57,8 @@ func (s *ServersService) Get(ctx context.Context, vHost string) (*Server, error)
 
 // CacheFlush flushes a cache-entry by name
 func (s *ServersService) CacheFlush(ctx context.Context, vHost string, domain string) (*CacheFlushResult, error) {
+	ctx = withOperation(ctx, Operation{Service: "Servers", Method: "CacheFlush", Zone: makeDomainCanonical(domain)})
+
 	query := url.Values{}
 	query.Add("domain", makeDomainCanonical(domain))
 	req, err := s.client.newRequest(ctx, http.MethodPut, path.Join("servers", vHost, "cache", "flush"), &query, nil)
@@ -2,6 +2,8 @@ package powerdns
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"path"
@@ -22,30 +24,194 @@ type Statistic struct {
 	Value interface{} `json:"value,omitempty"`
 }
 
-// List retrieves a list of Statistics
-func (s *StatisticsService) List(ctx context.Context) ([]Statistic, error) {
-	req, err := s.client.newRequest(ctx, http.MethodGet, path.Join("servers", s.client.VHost, "statistics"), nil, nil)
-	if err != nil {
-		return nil, err
+// TypedStatistic is a statistic decoded according to its type, which is one of StatisticItem, MapStatisticItem and RingStatisticItem
+type TypedStatistic interface {
+	// StatisticName returns the name of the statistic
+	StatisticName() string
+}
+
+// StatisticItem is a single counter or gauge
+type StatisticItem struct {
+	Name  string `json:"name"`
+	Value uint64 `json:"value,string"`
+}
+
+// SimpleStatisticItem is an entry of a MapStatisticItem or a RingStatisticItem
+type SimpleStatisticItem struct {
+	Name  string `json:"name"`
+	Value uint64 `json:"value,string"`
+}
+
+// MapStatisticItem is a set of counters, e.g. the responses by query type
+type MapStatisticItem struct {
+	Name    string                `json:"name"`
+	Entries []SimpleStatisticItem `json:"value"`
+}
+
+// RingStatisticItem contains the most frequent entries of a ring buffer, e.g. of the queried names
+type RingStatisticItem struct {
+	Name    string                `json:"name"`
+	Size    uint64                `json:"size,string"`
+	Entries []SimpleStatisticItem `json:"value"`
+}
+
+// StatisticName returns the name of the statistic
+func (s StatisticItem) StatisticName() string { return s.Name }
+
+// StatisticName returns the name of the statistic
+func (s MapStatisticItem) StatisticName() string { return s.Name }
+
+// StatisticName returns the name of the statistic
+func (s RingStatisticItem) StatisticName() string { return s.Name }
+
+// TypedStatistics is a list of statistics, which decodes each statistic according to its type
+type TypedStatistics []TypedStatistic
+
+// UnmarshalJSON decodes a list of statistics into their typed variants.
+// Statistics of unknown types, which newer PowerDNS versions may introduce, are skipped.
+func (t *TypedStatistics) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
 	}
 
+	statistics := make(TypedStatistics, 0, len(raw))
+	for _, message := range raw {
+		var header struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(message, &header); err != nil {
+			return err
+		}
+
+		var statistic TypedStatistic
+		var err error
+		switch header.Type {
+		case "StatisticItem":
+			statistic, err = decodeStatistic[StatisticItem](message)
+		case "MapStatisticItem":
+			statistic, err = decodeStatistic[MapStatisticItem](message)
+		case "RingStatisticItem":
+			statistic, err = decodeStatistic[RingStatisticItem](message)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("statistic %s: %w", header.Name, err)
+		}
+		statistics = append(statistics, statistic)
+	}
+
+	*t = statistics
+	return nil
+}
+
+func decodeStatistic[T TypedStatistic](message json.RawMessage) (TypedStatistic, error) {
+	var statistic T
+	err := json.Unmarshal(message, &statistic)
+	return statistic, err
+}
+
+// Counter returns the value of the StatisticItem with the given name, and whether it exists
+func (t TypedStatistics) Counter(name string) (uint64, bool) {
+	for _, statistic := range t {
+		if item, ok := statistic.(StatisticItem); ok && item.Name == name {
+			return item.Value, true
+		}
+	}
+	return 0, false
+}
+
+// StatisticsOption is a functional option for StatisticsService.List and StatisticsService.ListTyped.
+type StatisticsOption func(*statisticsOptions)
+
+type statisticsOptions struct {
+	withoutRings bool
+}
+
+// WithoutRings omits the ring statistics, which are expensive to compute on busy servers.
+func WithoutRings() StatisticsOption {
+	return func(o *statisticsOptions) {
+		o.withoutRings = true
+	}
+}
+
+func (o *statisticsOptions) query() *url.Values {
+	query := &url.Values{}
+	if o.withoutRings {
+		query.Set("includerings", "false")
+	}
+	return query
+}
+
+// List retrieves a list of Statistics
+func (s *StatisticsService) List(ctx context.Context, options ...StatisticsOption) ([]Statistic, error) {
+	ctx = withOperation(ctx, Operation{Service: "Statistics", Method: "List"})
+
 	statistics := make([]Statistic, 0)
-	_, err = s.client.do(req, &statistics)
+	err := s.list(ctx, options, &statistics)
+	return statistics, err
+}
 
+// ListTyped retrieves a list of Statistics decoded into their typed variants
+func (s *StatisticsService) ListTyped(ctx context.Context, options ...StatisticsOption) (TypedStatistics, error) {
+	ctx = withOperation(ctx, Operation{Service: "Statistics", Method: "ListTyped"})
+
+	statistics := make(TypedStatistics, 0)
+	err := s.list(ctx, options, &statistics)
 	return statistics, err
 }
 
+func (s *StatisticsService) list(ctx context.Context, options []StatisticsOption, v any) error {
+	o := &statisticsOptions{}
+	for _, option := range options {
+		option(o)
+	}
+
+	req, err := s.client.newRequest(ctx, http.MethodGet, path.Join("servers", s.client.VHost, "statistics"), o.query(), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(req, v)
+	return err
+}
+
 // Get retrieves certain Statistics
 func (s *StatisticsService) Get(ctx context.Context, statisticName string) ([]Statistic, error) {
+	ctx = withOperation(ctx, Operation{Service: "Statistics", Method: "Get"})
+
+	statistics := make([]Statistic, 0)
+	err := s.get(ctx, statisticName, &statistics)
+	return statistics, err
+}
+
+// Counter retrieves the value of the StatisticItem with the given name.
+// It returns an error wrapping ErrStatisticType if the statistic is a map or ring statistic.
+func (s *StatisticsService) Counter(ctx context.Context, statisticName string) (uint64, error) {
+	ctx = withOperation(ctx, Operation{Service: "Statistics", Method: "Counter"})
+
+	var statistics TypedStatistics
+	if err := s.get(ctx, statisticName, &statistics); err != nil {
+		return 0, err
+	}
+
+	value, ok := statistics.Counter(statisticName)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s is not a StatisticItem", ErrStatisticType, statisticName)
+	}
+	return value, nil
+}
+
+func (s *StatisticsService) get(ctx context.Context, statisticName string, v any) error {
 	query := url.Values{}
 	query.Add("statistic", statisticName)
 	req, err := s.client.newRequest(ctx, http.MethodGet, path.Join("servers", s.client.VHost, "statistics"), &query, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	statistics := make([]Statistic, 0)
-	_, err = s.client.do(req, &statistics)
-
-	return statistics, err
+	_, err = s.client.do(req, v)
+	return err
 }
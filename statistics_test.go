@@ -2,7 +2,10 @@ package powerdns
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -31,6 +34,37 @@ func registerStatisticsMockResponder() {
 	)
 }
 
+// registerTypedStatisticsMockResponder serves statistics with numeric values, as reported by PowerDNS, which can be decoded as TypedStatistics
+func registerTypedStatisticsMockResponder() {
+	httpmock.RegisterResponder("GET", generateTestAPIVHostURL()+"/statistics",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			ringMock := "{\"name\": \"logmessages\", \"size\": \"10000\", \"type\": \"RingStatisticItem\", \"value\": [{\"name\": \"gmysql Connection successful. Connected to database 'powerdns' on 'mariadb'.\", \"value\": \"235\"}]}"
+			statisticsMock := "[{\"name\": \"corrupt-packets\", \"type\": \"StatisticItem\", \"value\": \"0\"}, {\"name\": \"response-by-rcode\", \"type\": \"MapStatisticItem\", \"value\": [{\"name\": \"No Error\", \"value\": \"12\"}, {\"name\": \"NXDomain\", \"value\": \"3\"}]}, {\"name\": \"latency-histogram\", \"type\": \"HistogramStatisticItem\", \"value\": []}, " + ringMock + "]"
+			if req.URL.Query().Get("includerings") == "false" {
+				statisticsMock = "[{\"name\": \"corrupt-packets\", \"type\": \"StatisticItem\", \"value\": \"0\"}]"
+			}
+
+			statisticQueryString := req.URL.Query().Get("statistic")
+			if statisticQueryString != "" {
+				switch statisticQueryString {
+				case "corrupt-packets":
+					statisticsMock = "[{\"name\": \"corrupt-packets\", \"type\": \"StatisticItem\", \"value\": \"0\"}]"
+				case "queries":
+					statisticsMock = "[{\"name\": \"queries\", \"size\": \"10000\", \"type\": \"RingStatisticItem\", \"value\": []}]"
+				default:
+					return httpmock.NewStringResponse(http.StatusUnprocessableEntity, "Unprocessable Entity"), nil
+				}
+			}
+
+			return httpmock.NewStringResponse(http.StatusOK, statisticsMock), nil
+		},
+	)
+}
+
 func TestListStatistics(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -76,3 +110,106 @@ func TestGetStatisticsError(t *testing.T) {
 		t.Error("error is nil")
 	}
 }
+
+func TestListTypedStatistics(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerTypedStatisticsMockResponder()
+
+	p := initialisePowerDNSTestClient()
+	statistics, err := p.Statistics.ListTyped(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	types := make(map[string]bool)
+	for _, statistic := range statistics {
+		if statistic.StatisticName() == "" {
+			t.Errorf("unnamed statistic %+v", statistic)
+		}
+		types[reflect.TypeOf(statistic).Name()] = true
+	}
+	if !types["StatisticItem"] || !types["MapStatisticItem"] || !types["RingStatisticItem"] {
+		t.Errorf("unexpected statistics %+v", statistics)
+	}
+
+	statistics, err = p.Statistics.ListTyped(context.Background(), WithoutRings())
+	if err != nil || len(statistics) == 0 {
+		t.Errorf("unexpected statistics %+v: %v", statistics, err)
+	}
+	for _, statistic := range statistics {
+		if _, ok := statistic.(RingStatisticItem); ok {
+			t.Errorf("unexpected ring %+v", statistic)
+		}
+	}
+	if _, ok := statistics.Counter("corrupt-packets"); !ok {
+		t.Error("counter not found")
+	}
+	if _, ok := statistics.Counter("unknown"); ok {
+		t.Error("unknown counter found")
+	}
+}
+
+func TestListTypedStatisticsError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if _, err := p.Statistics.ListTyped(context.Background()); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestTypedStatisticsUnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		data          string
+		wantErrorType error
+	}{
+		{`{}`, nil},
+		{`["corrupt-packets"]`, nil},
+		{`[{"name": "corrupt-packets", "type": "StatisticItem", "value": "-1"}]`, nil},
+		{`[{"name": "response-by-rcode", "type": "MapStatisticItem", "value": "0"}]`, nil},
+		{`[{"name": "logmessages", "type": "RingStatisticItem", "size": 10000}]`, nil},
+	}
+
+	for i, tc := range testCases {
+		var statistics TypedStatistics
+		err := json.Unmarshal([]byte(tc.data), &statistics)
+		if err == nil || (tc.wantErrorType != nil && !errors.Is(err, tc.wantErrorType)) {
+			t.Errorf("TestCase%d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+func TestTypedStatisticsUnmarshalJSONUnknownType(t *testing.T) {
+	var statistics TypedStatistics
+	data := `[{"name": "latency-histogram", "type": "HistogramStatisticItem", "value": []}, {"name": "corrupt-packets", "type": "StatisticItem", "value": "1"}]`
+	if err := json.Unmarshal([]byte(data), &statistics); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(statistics, TypedStatistics{StatisticItem{Name: "corrupt-packets", Value: 1}}) {
+		t.Errorf("unexpected statistics %+v", statistics)
+	}
+}
+
+func TestStatisticsCounter(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerTypedStatisticsMockResponder()
+
+	p := initialisePowerDNSTestClient()
+	if _, err := p.Statistics.Counter(context.Background(), "corrupt-packets"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := p.Statistics.Counter(context.Background(), "queries"); !errors.Is(err, ErrStatisticType) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := p.Statistics.Counter(context.Background(), "unknown"); !errors.Is(err, ErrValidation) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestStatisticsCounterError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if _, err := p.Statistics.Counter(context.Background(), "corrupt-packets"); err == nil {
+		t.Error("error is nil")
+	}
+}
@@ -20,6 +20,8 @@ type TSIGKey struct {
 
 // List retrieves a list of TSIGKeys
 func (t *TSIGKeysService) List(ctx context.Context) ([]TSIGKey, error) {
+	ctx = withOperation(ctx, Operation{Service: "TSIGKeys", Method: "List"})
+
 	req, err := t.client.newRequest(ctx, http.MethodGet, path.Join("servers", t.client.VHost, "tsigkeys"), nil, nil)
 	if err != nil {
 		return nil, err
@@ -32,6 +34,8 @@ func (t *TSIGKeysService) List(ctx context.Context) ([]TSIGKey, error) {
 
 // Get returns a certain TSIGKeys
 func (t *TSIGKeysService) Get(ctx context.Context, id string) (*TSIGKey, error) {
+	ctx = withOperation(ctx, Operation{Service: "TSIGKeys", Method: "Get"})
+
 	req, err := t.client.newRequest(ctx, http.MethodGet, path.Join("servers", t.client.VHost, "tsigkeys", id), nil, nil)
 	if err != nil {
 		return nil, err
@@ -44,6 +48,8 @@ func (t *TSIGKeysService) Get(ctx context.Context, id string) (*TSIGKey, error)
 
 // Create a new TSIG Key setting empty string for key will generate it
 func (t *TSIGKeysService) Create(ctx context.Context, name, algorithm, key string) (*TSIGKey, error) {
+	ctx = withOperation(ctx, Operation{Service: "TSIGKeys", Method: "Create"})
+
 	reqTsigkey := TSIGKey{
 		Name:      &name,
 		Algorithm: &algorithm,
@@ -61,6 +67,8 @@ func (t *TSIGKeysService) Create(ctx context.Context, name, algorithm, key strin
 }
 
 func (t *TSIGKeysService) Change(ctx context.Context, id string, newKey TSIGKey) (*TSIGKey, error) {
+	ctx = withOperation(ctx, Operation{Service: "TSIGKeys", Method: "Change"})
+
 	req, err := t.client.newRequest(ctx, http.MethodPut, path.Join("servers", t.client.VHost, "tsigkeys", id), nil, newKey)
 	if err != nil {
 		return nil, err
@@ -72,6 +80,8 @@ func (t *TSIGKeysService) Change(ctx context.Context, id string, newKey TSIGKey)
 }
 
 func (t *TSIGKeysService) Delete(ctx context.Context, id string) error {
+	ctx = withOperation(ctx, Operation{Service: "TSIGKeys", Method: "Delete"})
+
 	req, err := t.client.newRequest(ctx, http.MethodDelete, path.Join("servers", t.client.VHost, "tsigkeys", id), nil, nil)
 	if err != nil {
 		return err
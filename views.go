@@ -0,0 +1,101 @@
+package powerdns
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ViewsService handles communication with the views related methods of the Client API.
+// Views require PowerDNS Authoritative Server 5.0 or later.
+type ViewsService service
+
+// Views structure with JSON API metadata
+type Views struct {
+	Views []string `json:"views"`
+}
+
+// View structure with JSON API metadata
+type View struct {
+	Zones []string `json:"zones"`
+}
+
+// viewZone is the request body to add a zone variant to a view
+type viewZone struct {
+	Name *string `json:"name"`
+}
+
+// ZoneVariant returns the ID of a variant of zone, e.g. "example.com..internal".
+// It can be used as domain with ZonesService, RecordsService and the other zone related services. An empty variant refers to the zone itself.
+func ZoneVariant(zone, variant string) string {
+	if variant == "" {
+		return makeDomainCanonical(zone)
+	}
+	return makeDomainCanonical(zone) + "." + variant
+}
+
+// SplitZoneVariant splits the ID of a zone variant into the canonical zone name and the variant, which is empty if the ID refers to a plain zone.
+func SplitZoneVariant(id string) (zone, variant string) {
+	id = trimDomain(id)
+
+	i := strings.LastIndex(id, "..")
+	if i < 0 || i+2 == len(id) || strings.Contains(id[i+2:], ".") {
+		return id + ".", ""
+	}
+	return trimDomain(id[:i]) + ".", id[i+2:]
+}
+
+// List retrieves the names of all views
+func (v *ViewsService) List(ctx context.Context) ([]string, error) {
+	ctx = withOperation(ctx, Operation{Service: "Views", Method: "List"})
+
+	req, err := v.client.newRequest(ctx, http.MethodGet, path.Join("servers", v.client.VHost, "views"), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	views := &Views{}
+	_, err = v.client.do(req, views)
+	return views.Views, err
+}
+
+// Get retrieves the IDs of the zone variants which are members of a given view
+func (v *ViewsService) Get(ctx context.Context, view string) ([]string, error) {
+	ctx = withOperation(ctx, Operation{Service: "Views", Method: "Get"})
+
+	req, err := v.client.newRequest(ctx, http.MethodGet, path.Join("servers", v.client.VHost, "views", view), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	members := &View{}
+	_, err = v.client.do(req, members)
+	return members.Zones, err
+}
+
+// AddZone adds a zone variant to a given view, which is created if it does not exist yet
+func (v *ViewsService) AddZone(ctx context.Context, view, domain string) error {
+	ctx = withOperation(ctx, Operation{Service: "Views", Method: "AddZone", Zone: makeDomainCanonical(domain)})
+
+	req, err := v.client.newRequest(ctx, http.MethodPost, path.Join("servers", v.client.VHost, "views", view), nil, viewZone{Name: String(zoneID(domain))})
+	if err != nil {
+		return err
+	}
+
+	_, err = v.client.do(req, nil)
+	return err
+}
+
+// RemoveZone removes a zone variant from a given view
+func (v *ViewsService) RemoveZone(ctx context.Context, view, domain string) error {
+	ctx = withOperation(ctx, Operation{Service: "Views", Method: "RemoveZone", Zone: makeDomainCanonical(domain)})
+
+	req, err := v.client.newRequest(ctx, http.MethodDelete, path.Join("servers", v.client.VHost, "views", view, zoneID(domain)), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = v.client.do(req, nil)
+	return err
+}
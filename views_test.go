@@ -0,0 +1,257 @@
+package powerdns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func registerViewsMockResponder(views map[string][]string) {
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/views",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			viewsMock := Views{Views: make([]string, 0, len(views))}
+			for view := range views {
+				viewsMock.Views = append(viewsMock.Views, view)
+			}
+			slices.Sort(viewsMock.Views)
+			return httpmock.NewJsonResponse(http.StatusOK, viewsMock)
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodGet, `=~^`+generateTestAPIVHostURL()+`/views/([^/]+)\z`,
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			zones, ok := views[httpmock.MustGetSubmatch(req, 1)]
+			if !ok {
+				return httpmock.NewStringResponse(http.StatusNotFound, "Not Found"), nil
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, View{Zones: zones})
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodPost, `=~^`+generateTestAPIVHostURL()+`/views/([^/]+)\z`,
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			var zone viewZone
+			if json.NewDecoder(req.Body).Decode(&zone) != nil || zone.Name == nil {
+				return httpmock.NewBytesResponse(http.StatusBadRequest, []byte{}), nil
+			}
+			view := httpmock.MustGetSubmatch(req, 1)
+			views[view] = append(views[view], *zone.Name)
+			return httpmock.NewBytesResponse(http.StatusNoContent, []byte{}), nil
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodDelete, `=~^`+generateTestAPIVHostURL()+`/views/([^/]+)/([^/]+)\z`,
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			view, zone := httpmock.MustGetSubmatch(req, 1), httpmock.MustGetSubmatch(req, 2)
+			i := slices.Index(views[view], zone)
+			if i < 0 {
+				return httpmock.NewStringResponse(http.StatusNotFound, "Not Found"), nil
+			}
+			views[view] = slices.Delete(views[view], i, i+1)
+			return httpmock.NewBytesResponse(http.StatusNoContent, []byte{}), nil
+		},
+	)
+}
+
+func TestZoneVariant(t *testing.T) {
+	testCases := []struct {
+		zone, variant string
+		wantID        string
+	}{
+		{"example.com", "internal", "example.com..internal"},
+		{"example.com.", "internal", "example.com..internal"},
+		{"example.com", "", "example.com."},
+		{".", "internal", "..internal"},
+	}
+
+	for _, tc := range testCases {
+		id := ZoneVariant(tc.zone, tc.variant)
+		if id != tc.wantID {
+			t.Errorf("ZoneVariant(%q, %q) = %q, want %q", tc.zone, tc.variant, id, tc.wantID)
+		}
+
+		zone, variant := SplitZoneVariant(id)
+		if zone != makeDomainCanonical(tc.zone) || variant != tc.variant {
+			t.Errorf("SplitZoneVariant(%q) = %q, %q", id, zone, variant)
+		}
+	}
+}
+
+func TestSplitZoneVariant(t *testing.T) {
+	testCases := []struct {
+		id                    string
+		wantZone, wantVariant string
+	}{
+		{"example.com..internal.", "example.com.", "internal"},
+		{"example.com...internal", "example.com.", "internal"},
+		{"example.com..", "example.com..", ""},
+		{"..internal", ".", "internal"},
+		{"example.com", "example.com.", ""},
+	}
+
+	for _, tc := range testCases {
+		if zone, variant := SplitZoneVariant(tc.id); zone != tc.wantZone || variant != tc.wantVariant {
+			t.Errorf("SplitZoneVariant(%q) = %q, %q", tc.id, zone, variant)
+		}
+	}
+}
+
+// generateTestView returns the name of a new view and adds the given zones to it, which are created unless mocks are used
+func generateTestView(t *testing.T, p *Client, zones ...string) string {
+	view := fmt.Sprintf("test%d", rand.Int())
+	for _, zone := range zones {
+		if httpmock.Disabled() {
+			if _, err := p.Zones.AddNative(context.Background(), zone, true, "", false, "", "", true, []string{"ns.foo.tld."}); err != nil {
+				t.Fatalf("%s", err)
+			}
+		}
+		if err := p.Views.AddZone(context.Background(), view, zone); err != nil {
+			t.Fatalf("%s", err)
+		}
+	}
+	return view
+}
+
+func TestListViews(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerViewsMockResponder(map[string][]string{})
+
+	p := initialisePowerDNSTestClient()
+	view := generateTestView(t, p, generateNativeZone(false))
+	views, err := p.Views.List(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+	}
+	if !slices.Contains(views, view) {
+		t.Errorf("unexpected views %v", views)
+	}
+}
+
+func TestListViewsError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if _, err := p.Views.List(context.Background()); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestGetView(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerViewsMockResponder(map[string][]string{})
+
+	p := initialisePowerDNSTestClient()
+	domain := generateNativeZone(false)
+	want := []string{makeDomainCanonical(domain), ZoneVariant(domain, "internal")}
+	view := generateTestView(t, p, want...)
+
+	zones, err := p.Views.Get(context.Background(), view)
+	if err != nil {
+		t.Errorf("%s", err)
+	}
+	slices.Sort(zones)
+	if !slices.Equal(zones, want) {
+		t.Errorf("unexpected zones %v", zones)
+	}
+}
+
+func TestGetViewError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if _, err := p.Views.Get(context.Background(), "internal"); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestAddAndRemoveViewZone(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerViewsMockResponder(map[string][]string{})
+
+	p := initialisePowerDNSTestClient()
+	variant, domain := ZoneVariant(generateNativeZone(false), "internal"), generateNativeZone(true)
+	view := generateTestView(t, p, variant)
+	if err := p.Views.AddZone(context.Background(), view, domain); err != nil {
+		t.Errorf("%s", err)
+	}
+	if err := p.Views.RemoveZone(context.Background(), view, variant); err != nil {
+		t.Errorf("%s", err)
+	}
+
+	zones, err := p.Views.Get(context.Background(), view)
+	if err != nil || !slices.Equal(zones, []string{makeDomainCanonical(domain)}) {
+		t.Errorf("unexpected zones %v: %v", zones, err)
+	}
+}
+
+func TestAddViewZoneError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if err := p.Views.AddZone(context.Background(), "internal", "example.com..internal"); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestRemoveViewZoneError(t *testing.T) {
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if err := p.Views.RemoveZone(context.Background(), "internal", "example.com..internal"); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestGetZoneVariant(t *testing.T) {
+	domain := generateNativeZone(false)
+	variant := ZoneVariant(domain, "internal")
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerViewsMockResponder(map[string][]string{})
+
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/zones/"+variant,
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, Zone{ID: String(variant), Name: String(variant)})
+		},
+	)
+
+	p := initialisePowerDNSTestClient()
+	generateTestView(t, p, variant)
+	zone, err := p.Zones.Get(context.Background(), ZoneVariant(makeDomainCanonical(domain), "internal"))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if *zone.ID != variant {
+		t.Errorf("unexpected zone %+v", zone)
+	}
+
+	rrSets, err := p.Records.Get(context.Background(), variant, "www."+domain, RRTypePtr(RRTypeA))
+	if err != nil || rrSets != nil {
+		t.Errorf("unexpected RRsets %+v: %v", rrSets, err)
+	}
+}
@@ -0,0 +1,479 @@
+package powerdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+const maxZoneFileIncludeDepth = 10
+
+// ZoneFileOption is a functional option for ParseZoneFile.
+type ZoneFileOption func(*zoneFileParser)
+
+// WithIncludeFS is an option for ParseZoneFile to resolve $INCLUDE directives within fsys.
+// Without this option, $INCLUDE directives are rejected.
+func WithIncludeFS(fsys fs.FS) ZoneFileOption {
+	return func(p *zoneFileParser) {
+		p.fsys = fsys
+	}
+}
+
+// WithDefaultTTL is an option for ParseZoneFile to set the TTL of records which precede any $TTL directive or explicit TTL.
+func WithDefaultTTL(ttl uint32) ZoneFileOption {
+	return func(p *zoneFileParser) {
+		p.ttl = &ttl
+	}
+}
+
+type zoneFileParser struct {
+	fsys    fs.FS
+	ttl     *uint32
+	lastTTL *uint32
+	depth   int
+	rrSets  []RRset
+	index   map[rrSetKey]int
+}
+
+type zoneFileToken struct {
+	value  string
+	quoted bool
+}
+
+type zoneFileEntry struct {
+	line       int
+	blankOwner bool
+	tokens     []zoneFileToken
+}
+
+// ParseZoneFile parses a BIND-style master file into RRsets.
+//
+// Relative names are qualified with origin, which may be empty if the file contains an $ORIGIN directive or only absolute names.
+// $ORIGIN, $TTL and $INCLUDE directives, omitted owners, TTL units (e.g. "1h30m"), parentheses and multi-string TXT records are supported.
+// Records with the same name and type are grouped into one RRset, which gets the TTL of its first record.
+func ParseZoneFile(r io.Reader, origin string, options ...ZoneFileOption) ([]RRset, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &zoneFileParser{index: make(map[rrSetKey]int)}
+	for _, option := range options {
+		option(p)
+	}
+
+	if origin != "" {
+		origin = makeDomainCanonical(origin)
+	}
+
+	if err := p.parse("", string(data), origin); err != nil {
+		return nil, err
+	}
+
+	return p.rrSets, nil
+}
+
+func (p *zoneFileParser) parse(fileName string, data string, origin string) error {
+	entries, err := lexZoneFile(data)
+	if err != nil {
+		return zoneFileError(fileName, 0, err)
+	}
+
+	lastOwner := ""
+	for _, entry := range entries {
+		if !entry.blankOwner && strings.HasPrefix(entry.tokens[0].value, "$") {
+			if origin, err = p.parseDirective(entry, origin); err != nil {
+				return zoneFileError(fileName, entry.line, err)
+			}
+			continue
+		}
+
+		if lastOwner, err = p.parseRecord(entry, origin, lastOwner); err != nil {
+			return zoneFileError(fileName, entry.line, err)
+		}
+	}
+
+	return nil
+}
+
+func zoneFileError(fileName string, line int, err error) error {
+	var location []string
+	if fileName != "" {
+		location = append(location, fileName)
+	}
+	if line > 0 {
+		location = append(location, fmt.Sprintf("line %d", line))
+	}
+	if len(location) == 0 {
+		return err
+	}
+	return fmt.Errorf("%s: %w", strings.Join(location, ", "), err)
+}
+
+func (p *zoneFileParser) parseDirective(entry zoneFileEntry, origin string) (string, error) {
+	directive := strings.ToUpper(entry.tokens[0].value)
+	args := entry.tokens[1:]
+
+	switch directive {
+	case "$ORIGIN":
+		if len(args) != 1 {
+			return origin, errors.New("$ORIGIN requires exactly one argument")
+		}
+		return qualifyZoneFileName(args[0].value, origin)
+	case "$TTL":
+		if len(args) != 1 {
+			return origin, errors.New("$TTL requires exactly one argument")
+		}
+		ttl, err := parseZoneFileTTL(args[0].value)
+		if err != nil {
+			return origin, err
+		}
+		p.ttl = &ttl
+		return origin, nil
+	case "$INCLUDE":
+		return origin, p.include(args, origin)
+	default:
+		return origin, fmt.Errorf("unsupported directive %s", directive)
+	}
+}
+
+func (p *zoneFileParser) include(args []zoneFileToken, origin string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New("$INCLUDE requires a file name and an optional origin")
+	}
+
+	if p.fsys == nil {
+		return errors.New("$INCLUDE is not allowed without WithIncludeFS")
+	}
+
+	if p.depth >= maxZoneFileIncludeDepth {
+		return errors.New("$INCLUDE is nested too deeply")
+	}
+
+	includeOrigin := origin
+	if len(args) == 2 {
+		var err error
+		if includeOrigin, err = qualifyZoneFileName(args[1].value, origin); err != nil {
+			return err
+		}
+	}
+
+	data, err := fs.ReadFile(p.fsys, args[0].value)
+	if err != nil {
+		return err
+	}
+
+	p.depth++
+	defer func() {
+		p.depth--
+	}()
+
+	// The origin of the including file is not affected by $ORIGIN directives within the included file.
+	return p.parse(args[0].value, string(data), includeOrigin)
+}
+
+func (p *zoneFileParser) parseRecord(entry zoneFileEntry, origin string, lastOwner string) (string, error) {
+	tokens := entry.tokens
+
+	owner := lastOwner
+	if !entry.blankOwner {
+		var err error
+		if owner, err = qualifyZoneFileName(tokens[0].value, origin); err != nil {
+			return lastOwner, err
+		}
+		tokens = tokens[1:]
+	}
+	if owner == "" {
+		return lastOwner, errors.New("record without owner")
+	}
+
+	var ttl *uint32
+	for len(tokens) > 0 {
+		if value, err := parseZoneFileTTL(tokens[0].value); err == nil && ttl == nil {
+			ttl = &value
+		} else if strings.EqualFold(tokens[0].value, "IN") {
+			// IN is the only supported class and therefore the default.
+		} else {
+			break
+		}
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) < 2 {
+		return owner, errors.New("record without type or content")
+	}
+
+	recordType := RRType(strings.ToUpper(tokens[0].value))
+	content, err := zoneFileRecordContent(recordType, tokens[1:], origin)
+	if err != nil {
+		return owner, err
+	}
+
+	switch {
+	case ttl != nil:
+		p.lastTTL = ttl
+	case p.ttl != nil:
+		ttl = p.ttl
+	case p.lastTTL != nil:
+		// In absence of a $TTL directive, the last explicit TTL is the default for subsequent records.
+		ttl = p.lastTTL
+	default:
+		return owner, errors.New("record without TTL and no default TTL")
+	}
+
+	p.addRecord(owner, recordType, *ttl, content)
+	return owner, nil
+}
+
+func (p *zoneFileParser) addRecord(name string, recordType RRType, ttl uint32, content string) {
+	key := rrSetKey{name: strings.ToLower(name), recordType: recordType}
+	record := Record{Content: String(content), Disabled: Bool(false)}
+
+	if i, ok := p.index[key]; ok {
+		p.rrSets[i].Records = append(p.rrSets[i].Records, record)
+		return
+	}
+
+	p.index[key] = len(p.rrSets)
+	p.rrSets = append(p.rrSets, RRset{
+		Name:    String(name),
+		Type:    RRTypePtr(recordType),
+		TTL:     Uint32(ttl),
+		Records: []Record{record},
+	})
+}
+
+func zoneFileRecordContent(recordType RRType, tokens []zoneFileToken, origin string) (string, error) {
+	fields := make([]string, len(tokens))
+	for i, token := range tokens {
+		fields[i] = token.value
+
+		if !token.quoted && (recordType == RRTypeTXT || recordType == RRTypeSPF) {
+			fields[i] = `"` + token.value + `"`
+		}
+	}
+
+	// PowerDNS expects the SOA timers in seconds, whereas BIND accepts TTL units.
+	if recordType == RRTypeSOA {
+		for i := 3; i < len(fields) && i < 7; i++ {
+			seconds, err := parseZoneFileTTL(fields[i])
+			if err != nil {
+				return "", err
+			}
+			fields[i] = strconv.FormatUint(uint64(seconds), 10)
+		}
+	}
+
+	for _, i := range rdataDomainNameFields[recordType] {
+		if i >= len(tokens) || tokens[i].quoted || tokens[i].value == "." {
+			continue
+		}
+
+		var err error
+		if fields[i], err = qualifyZoneFileName(tokens[i].value, origin); err != nil {
+			return "", err
+		}
+	}
+
+	return strings.Join(fields, " "), nil
+}
+
+func qualifyZoneFileName(name string, origin string) (string, error) {
+	if name == "@" {
+		if origin == "" {
+			return "", errors.New("@ used without origin")
+		}
+		return origin, nil
+	}
+
+	if strings.HasSuffix(name, ".") && !strings.HasSuffix(name, `\.`) {
+		return name, nil
+	}
+
+	if origin == "" {
+		return "", fmt.Errorf("relative name %q used without origin", name)
+	}
+
+	if origin == "." {
+		return name + ".", nil
+	}
+	return name + "." + origin, nil
+}
+
+func parseZoneFileTTL(value string) (uint32, error) {
+	if value == "" || value[0] < '0' || value[0] > '9' {
+		return 0, fmt.Errorf("invalid TTL %q", value)
+	}
+
+	if ttl, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return uint32(ttl), nil
+	}
+
+	units := map[byte]uint64{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	var ttl, number uint64
+	hasNumber := false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= '0' && c <= '9' {
+			number = number*10 + uint64(c-'0')
+			hasNumber = true
+			continue
+		}
+
+		unit, ok := units[c|0x20]
+		if !ok || !hasNumber {
+			return 0, fmt.Errorf("invalid TTL %q", value)
+		}
+		ttl += number * unit
+		number, hasNumber = 0, false
+	}
+
+	if hasNumber || ttl > 1<<32-1 {
+		return 0, fmt.Errorf("invalid TTL %q", value)
+	}
+	return uint32(ttl), nil
+}
+
+func lexZoneFile(data string) ([]zoneFileEntry, error) {
+	entries := make([]zoneFileEntry, 0)
+	current := zoneFileEntry{line: 1}
+	var token strings.Builder
+	inToken, inQuotes, quoted := false, false, false
+	depth, line := 0, 1
+	atLineStart := true
+
+	flushToken := func() {
+		if inToken {
+			current.tokens = append(current.tokens, zoneFileToken{value: token.String(), quoted: quoted})
+		}
+		token.Reset()
+		inToken, quoted = false, false
+	}
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if atLineStart {
+			current = zoneFileEntry{line: line, blankOwner: c == ' ' || c == '\t'}
+			atLineStart = false
+		}
+
+		switch {
+		case inQuotes:
+			token.WriteByte(c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				token.WriteByte(data[i])
+				c = data[i]
+			} else if c == '"' {
+				inQuotes = false
+				flushToken()
+			}
+			if c == '\n' {
+				line++
+			}
+		case c == '\\':
+			inToken = true
+			token.WriteByte(c)
+			if i+1 < len(data) {
+				i++
+				token.WriteByte(data[i])
+			}
+		case c == '"':
+			flushToken()
+			token.WriteByte(c)
+			inToken, inQuotes, quoted = true, true, true
+		case c == ';':
+			flushToken()
+			for i+1 < len(data) && data[i+1] != '\n' {
+				i++
+			}
+		case c == '(':
+			flushToken()
+			depth++
+		case c == ')':
+			flushToken()
+			if depth--; depth < 0 {
+				return nil, fmt.Errorf("line %d: unbalanced parentheses", line)
+			}
+		case c == '\n':
+			flushToken()
+			if depth == 0 {
+				if len(current.tokens) > 0 {
+					entries = append(entries, current)
+				}
+				atLineStart = true
+			}
+			line++
+		case c == ' ' || c == '\t' || c == '\r':
+			flushToken()
+		default:
+			inToken = true
+			token.WriteByte(c)
+		}
+	}
+
+	if inQuotes {
+		return nil, fmt.Errorf("line %d: unterminated quoted string", line)
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", line)
+	}
+
+	flushToken()
+	if !atLineStart && len(current.tokens) > 0 {
+		entries = append(entries, current)
+	}
+
+	return entries, nil
+}
+
+// RRsets parses an exported zone into RRsets grouped by name and type.
+func (e Export) RRsets() ([]RRset, error) {
+	return ParseZoneFile(strings.NewReader(string(e)), "")
+}
+
+// NewExport renders RRsets in the format of ZonesService.Export, which can be parsed by Export.RRsets and ParseZoneFile.
+// Disabled records are omitted, since they are not part of the zone.
+func NewExport(rrSets []RRset) Export {
+	var b strings.Builder
+	for _, rrSet := range rrSets {
+		for _, record := range rrSet.Records {
+			if BoolValue(record.Disabled) {
+				continue
+			}
+			_, _ = fmt.Fprintf(&b, "%s\t%d\tIN\t%s\t%s\n", makeDomainCanonical(StringValue(rrSet.Name)), Uint32Value(rrSet.TTL), *rrSet.Type, StringValue(record.Content))
+		}
+	}
+	return Export(b.String())
+}
+
+// Import creates a zone with the given RRsets, e.g. the result of ParseZoneFile.
+// If the zone already exists, its content is replaced: The given RRsets are written and all other RRsets except SOA are deleted in a single PATCH.
+// Zone attributes which are set in zone are applied to existing zones as well.
+func (z *ZonesService) Import(ctx context.Context, zone *Zone, rrSets []RRset) (*Zone, error) {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Import", Zone: makeDomainCanonical(StringValue(zone.Name))})
+
+	newZone := *zone
+	newZone.RRsets = rrSets
+	if newZone.Kind == nil {
+		newZone.Kind = ZoneKindPtr(NativeZoneKind)
+	}
+
+	createdZone, err := z.Add(ctx, &newZone)
+	if !errors.Is(err, ErrZoneAlreadyExists) {
+		return createdZone, err
+	}
+
+	desiredZone := *zone
+	desiredZone.RRsets = rrSets
+	if _, err := z.Reconcile(ctx, &desiredZone); err != nil {
+		return nil, err
+	}
+
+	return z.Get(ctx, StringValue(zone.Name))
+}
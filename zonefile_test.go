@@ -0,0 +1,415 @@
+package powerdns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"testing/iotest"
+
+	"github.com/jarcoal/httpmock"
+)
+
+const testZoneFile = `; Example zone
+$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1 hostmaster (
+		2024010101 ; serial
+		3h         ; refresh
+		1h         ; retry
+		1w         ; expire
+		1h )       ; minimum
+	IN	NS	ns1
+	IN	NS	ns2.example.net.
+	IN	MX	10 mail
+	IN	MX	0 .
+ns1	300	IN	A	192.0.2.1
+www	IN	300	A	192.0.2.2
+	A	192.0.2.3
+	AAAA	2001:db8::2
+txt		TXT	"v=spf1 -all" "second \"string\""
+	TXT	unquoted
+_sip._tcp	SRV	10 60 5060 sip
+mail	CNAME	@
+$ORIGIN sub.example.com.
+host	A	192.0.2.4
+`
+
+func TestParseZoneFile(t *testing.T) {
+	rrSets, err := ParseZoneFile(strings.NewReader(testZoneFile), "")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	want := []struct {
+		name     string
+		rrType   RRType
+		ttl      uint32
+		contents []string
+	}{
+		{"example.com.", RRTypeSOA, 3600, []string{"ns1.example.com. hostmaster.example.com. 2024010101 10800 3600 604800 3600"}},
+		{"example.com.", RRTypeNS, 3600, []string{"ns1.example.com.", "ns2.example.net."}},
+		{"example.com.", RRTypeMX, 3600, []string{"10 mail.example.com.", "0 ."}},
+		{"ns1.example.com.", RRTypeA, 300, []string{"192.0.2.1"}},
+		{"www.example.com.", RRTypeA, 300, []string{"192.0.2.2", "192.0.2.3"}},
+		{"www.example.com.", RRTypeAAAA, 3600, []string{"2001:db8::2"}},
+		{"txt.example.com.", RRTypeTXT, 3600, []string{`"v=spf1 -all" "second \"string\""`, `"unquoted"`}},
+		{"_sip._tcp.example.com.", RRTypeSRV, 3600, []string{"10 60 5060 sip.example.com."}},
+		{"mail.example.com.", RRTypeCNAME, 3600, []string{"example.com."}},
+		{"host.sub.example.com.", RRTypeA, 3600, []string{"192.0.2.4"}},
+	}
+
+	if len(rrSets) != len(want) {
+		t.Fatalf("Expected %d RRsets, got %d", len(want), len(rrSets))
+	}
+
+	for i, w := range want {
+		rrSet := rrSets[i]
+		if *rrSet.Name != w.name || *rrSet.Type != w.rrType || *rrSet.TTL != w.ttl {
+			t.Errorf("Unexpected RRset %d: %s %s %d", i, *rrSet.Name, *rrSet.Type, *rrSet.TTL)
+		}
+		if len(rrSet.Records) != len(w.contents) {
+			t.Errorf("Unexpected records in RRset %d: %v", i, rrSet.Records)
+			continue
+		}
+		for j, content := range w.contents {
+			if *rrSet.Records[j].Content != content || BoolValue(rrSet.Records[j].Disabled) {
+				t.Errorf("Unexpected record content %q, want %q", *rrSet.Records[j].Content, content)
+			}
+		}
+	}
+}
+
+func TestParseZoneFileWithOrigin(t *testing.T) {
+	rrSets, err := ParseZoneFile(strings.NewReader("www 60 A 192.0.2.1\nfoo 60 CNAME www\n"), "example.com")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if *rrSets[0].Name != "www.example.com." || *rrSets[1].Records[0].Content != "www.example.com." {
+		t.Errorf("Unexpected RRsets: %v", rrSets)
+	}
+}
+
+func TestParseZoneFileWithRootOrigin(t *testing.T) {
+	rrSets, err := ParseZoneFile(strings.NewReader("com 60 NS a.gtld-servers.net."), ".")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if *rrSets[0].Name != "com." {
+		t.Errorf("Unexpected name: %s", *rrSets[0].Name)
+	}
+}
+
+func TestParseZoneFileDefaultTTL(t *testing.T) {
+	rrSets, err := ParseZoneFile(strings.NewReader("a A 192.0.2.1\nb 60 A 192.0.2.2\nc A 192.0.2.3"), "example.com", WithDefaultTTL(120))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if *rrSets[0].TTL != 120 || *rrSets[1].TTL != 60 || *rrSets[2].TTL != 120 {
+		t.Errorf("Unexpected TTLs: %d %d %d", *rrSets[0].TTL, *rrSets[1].TTL, *rrSets[2].TTL)
+	}
+
+	rrSets, err = ParseZoneFile(strings.NewReader("a 60 A 192.0.2.1\nb A 192.0.2.2"), "example.com")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if *rrSets[1].TTL != 60 {
+		t.Errorf("Last explicit TTL must be the default, got %d", *rrSets[1].TTL)
+	}
+
+	rrSets, err = ParseZoneFile(strings.NewReader("a 300 A 192.0.2.1\nb 600 A 192.0.2.2\nc A 192.0.2.3"), "example.com")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if *rrSets[2].TTL != 600 {
+		t.Errorf("Last explicit TTL must be the default, got %d", *rrSets[2].TTL)
+	}
+}
+
+func TestParseZoneFileInclude(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hosts.zone":  {Data: []byte("$ORIGIN other.example.\nhost 60 A 192.0.2.1\n$INCLUDE nested.zone\n")},
+		"nested.zone": {Data: []byte("nested 60 A 192.0.2.2\n")},
+		"loop.zone":   {Data: []byte("$INCLUDE loop.zone\n")},
+	}
+
+	rrSets, err := ParseZoneFile(strings.NewReader("$INCLUDE hosts.zone sub\nafter 60 A 192.0.2.3\n"), "example.com", WithIncludeFS(fsys))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	wantNames := []string{"host.other.example.", "nested.other.example.", "after.example.com."}
+	for i, name := range wantNames {
+		if *rrSets[i].Name != name {
+			t.Errorf("Unexpected name %s, want %s", *rrSets[i].Name, name)
+		}
+	}
+
+	rrSets, err = ParseZoneFile(strings.NewReader("$INCLUDE nested.zone sub\n"), "example.com", WithIncludeFS(fsys))
+	if err != nil || *rrSets[0].Name != "nested.sub.example.com." {
+		t.Errorf("Include origin not applied: %v", err)
+	}
+
+	if _, err := ParseZoneFile(strings.NewReader("$INCLUDE loop.zone\n"), "example.com", WithIncludeFS(fsys)); err == nil || !strings.Contains(err.Error(), "nested too deeply") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, err := ParseZoneFile(strings.NewReader("$INCLUDE missing.zone\n"), "example.com", WithIncludeFS(fsys)); err == nil {
+		t.Error("error is nil")
+	}
+
+	if _, err := ParseZoneFile(strings.NewReader("$INCLUDE nested.zone\n"), "example.com"); err == nil || !strings.Contains(err.Error(), "WithIncludeFS") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestParseZoneFileErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"broken.zone": {Data: []byte("www 60 A")},
+	}
+
+	testCases := []struct {
+		desc    string
+		zone    string
+		origin  string
+		wantErr string
+	}{
+		{"unbalanced close", "www 60 A 192.0.2.1 )", "example.com", "line 1: unbalanced parentheses"},
+		{"unbalanced open", "www 60 A ( 192.0.2.1", "example.com", "line 1: unbalanced parentheses"},
+		{"unterminated quote", "www 60 TXT \"foo", "example.com", "line 1: unterminated quoted string"},
+		{"missing content", "\nwww 60 A", "example.com", "line 2: record without type or content"},
+		{"missing owner", " 60 A 192.0.2.1", "example.com", "line 1: record without owner"},
+		{"missing TTL", "www A 192.0.2.1", "example.com", "line 1: record without TTL and no default TTL"},
+		{"relative owner without origin", "www 60 A 192.0.2.1", "", "line 1: relative name \"www\" used without origin"},
+		{"relative content without origin", "www.example.com. 60 CNAME foo", "", "line 1: relative name \"foo\" used without origin"},
+		{"@ without origin", "@ 60 A 192.0.2.1", "", "line 1: @ used without origin"},
+		{"invalid $ORIGIN", "$ORIGIN", "", "line 1: $ORIGIN requires exactly one argument"},
+		{"invalid $TTL", "$TTL", "", "line 1: $TTL requires exactly one argument"},
+		{"invalid $TTL value", "$TTL forever", "", "line 1: invalid TTL \"forever\""},
+		{"invalid $INCLUDE", "$INCLUDE", "", "line 1: $INCLUDE requires a file name and an optional origin"},
+		{"invalid $INCLUDE origin", "$INCLUDE broken.zone foo", "", "line 1: relative name \"foo\" used without origin"},
+		{"broken include", "$INCLUDE broken.zone", "example.com", "line 1: broken.zone, line 1: record without type or content"},
+		{"invalid SOA timer", "@ 60 SOA ns1 hostmaster 1 forever 1h 1w 1h", "example.com", "line 1: invalid TTL \"forever\""},
+		{"unsupported directive", "$GENERATE 1-10 host$ A 192.0.2.$", "example.com", "line 1: unsupported directive $GENERATE"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := ParseZoneFile(strings.NewReader(tc.zone), tc.origin, WithIncludeFS(fsys))
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("read error", func(t *testing.T) {
+		if _, err := ParseZoneFile(iotest.ErrReader(errors.New("read error")), "example.com"); err == nil {
+			t.Error("error is nil")
+		}
+	})
+}
+
+func TestParseZoneFileTTL(t *testing.T) {
+	testCases := []struct {
+		value   string
+		wantTTL uint32
+		wantErr bool
+	}{
+		{"3600", 3600, false},
+		{"1h30m", 5400, false},
+		{"1W2D", 777600, false},
+		{"10s", 10, false},
+		{"", 0, true},
+		{"h1", 0, true},
+		{"1x", 0, true},
+		{"1h30", 0, true},
+		{"100000w", 0, true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TestCase%d", i), func(t *testing.T) {
+			ttl, err := parseZoneFileTTL(tc.value)
+			if ttl != tc.wantTTL || (err != nil) != tc.wantErr {
+				t.Errorf("Unexpected result for %q: %d, %v", tc.value, ttl, err)
+			}
+		})
+	}
+}
+
+func TestLexZoneFile(t *testing.T) {
+	entries, err := lexZoneFile("a\\ b 60 TXT \"multi\nline\" \"esc\\\"aped\" ; comment\n\t\r\n(x\ny)")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Unexpected entries: %v", entries)
+	}
+
+	wantTokens := []zoneFileToken{{`a\ b`, false}, {"60", false}, {"TXT", false}, {"\"multi\nline\"", true}, {`"esc\"aped"`, true}}
+	for i, token := range wantTokens {
+		if entries[0].tokens[i] != token {
+			t.Errorf("Unexpected token %v, want %v", entries[0].tokens[i], token)
+		}
+	}
+
+	if entries[1].line != 4 || len(entries[1].tokens) != 2 {
+		t.Errorf("Unexpected entry: %v", entries[1])
+	}
+
+	entries, _ = lexZoneFile("trailing\\")
+	if entries[0].tokens[0].value != "trailing\\" {
+		t.Errorf("Unexpected token: %v", entries[0].tokens[0])
+	}
+}
+
+func registerImportMockResponder(testDomain string, exists bool) {
+	httpmock.RegisterResponder(http.MethodPost, generateTestAPIVHostURL()+"/zones",
+		func(req *http.Request) (*http.Response, error) {
+			var zone Zone
+			_ = json.NewDecoder(req.Body).Decode(&zone)
+			if exists {
+				return httpmock.NewJsonResponse(http.StatusConflict, Error{Message: "Domain '" + makeDomainCanonical(testDomain) + "' already exists"})
+			}
+			return httpmock.NewJsonResponse(http.StatusCreated, zone)
+		},
+	)
+
+	zoneURL := generateTestAPIVHostURL() + "/zones/" + makeDomainCanonical(testDomain)
+	httpmock.RegisterResponder(http.MethodGet, zoneURL,
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusOK, generateTestLiveZone(testDomain))
+		},
+	)
+	httpmock.RegisterResponder(http.MethodPatch, zoneURL, httpmock.NewBytesResponder(http.StatusNoContent, []byte{}))
+}
+
+func TestImportZone(t *testing.T) {
+	t.Run("new zone", func(t *testing.T) {
+		testDomain := generateNativeZone(false)
+		rrSets, _ := ParseZoneFile(strings.NewReader("www 60 A 192.0.2.1\n"), testDomain)
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerImportMockResponder(testDomain, false)
+
+		var posted []Zone
+		var patches []RRsets
+		p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithMiddleware(recordPayloads(http.MethodPost, &posted), recordPayloads(http.MethodPatch, &patches)))
+		zone, err := p.Zones.Import(context.Background(), &Zone{Name: String(testDomain)}, rrSets)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if len(posted) != 1 || len(posted[0].RRsets) != 1 || *posted[0].Kind != NativeZoneKind || len(patches) != 0 {
+			t.Errorf("Unexpected requests: %v, %v", posted, patches)
+		}
+		if *zone.Name != makeDomainCanonical(testDomain) {
+			t.Errorf("Unexpected zone: %v", zone)
+		}
+	})
+
+	t.Run("existing zone", func(t *testing.T) {
+		testDomain := generateLiveNativeZone(t)
+		rrSets, _ := ParseZoneFile(strings.NewReader("www 60 A 192.0.2.1\n"), testDomain)
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerImportMockResponder(testDomain, true)
+
+		var patches []RRsets
+		p := New(testBaseURL, testVHost, WithAPIKey(testAPIKey), WithMiddleware(recordPayloads(http.MethodPatch, &patches)))
+		zone, err := p.Zones.Import(context.Background(), &Zone{Name: String(testDomain)}, rrSets)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if len(patches) != 1 {
+			t.Fatalf("Expected 1 PATCH, got %d", len(patches))
+		}
+
+		changeTypes := make(map[ChangeType]int)
+		for _, rrSet := range patches[0].Sets {
+			if *rrSet.Type == RRTypeSOA {
+				t.Error("SOA must not be deleted")
+			}
+			changeTypes[*rrSet.ChangeType]++
+		}
+		if changeTypes[ChangeTypeReplace] != 1 || changeTypes[ChangeTypeDelete] != 4 {
+			t.Errorf("Unexpected changes: %v", changeTypes)
+		}
+		if zone == nil || *zone.Name != makeDomainCanonical(testDomain) {
+			t.Errorf("Unexpected zone: %v", zone)
+		}
+	})
+}
+
+func TestImportZoneError(t *testing.T) {
+	testDomain := generateLiveNativeZone(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerImportMockResponder(testDomain, true)
+
+	p := initialisePowerDNSTestClient()
+	rrSets := []RRset{{Name: String("new." + makeDomainCanonical(testDomain)), Type: RRTypePtr(RRTypeA)}}
+	if _, err := p.Zones.Import(context.Background(), &Zone{Name: String(testDomain)}, rrSets); err == nil {
+		t.Error("error is nil")
+	}
+
+	p.BaseURL = "://"
+	if _, err := p.Zones.Import(context.Background(), &Zone{Name: String(testDomain)}, rrSets); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestExportRRsets(t *testing.T) {
+	export := Export("example.com.\t3600\tIN\tSOA\tns1.example.com. hostmaster.example.com. 1 10800 3600 604800 3600\n" +
+		"example.com.\t3600\tIN\tNS\tns1.example.com.\n" +
+		"example.com.\t3600\tIN\tNS\tns2.example.com.\n" +
+		"www.example.com.\t300\tIN\tTXT\t\"foo; bar\" \"baz\"\n")
+
+	rrSets, err := export.RRsets()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if len(rrSets) != 3 || len(rrSets[1].Records) != 2 || *rrSets[2].Records[0].Content != `"foo; bar" "baz"` || *rrSets[2].TTL != 300 {
+		t.Errorf("Unexpected RRsets: %v", rrSets)
+	}
+
+	if _, err := Export("www 300 IN A 192.0.2.1").RRsets(); err == nil {
+		t.Error("error is nil")
+	}
+}
+
+func TestNewExport(t *testing.T) {
+	rrSets := []RRset{
+		{Name: String("example.com"), Type: RRTypePtr(RRTypeMX), TTL: Uint32(60), Records: []Record{{Content: String("10 mail.example.com.")}, {Content: String("20 disabled.example.com."), Disabled: Bool(true)}}},
+		{Name: String("www.example.com."), Type: RRTypePtr(RRTypeTXT), TTL: Uint32(300), Records: []Record{{Content: String(`"foo"`)}}},
+	}
+
+	want := "example.com.\t60\tIN\tMX\t10 mail.example.com.\nwww.example.com.\t300\tIN\tTXT\t\"foo\"\n"
+	if export := NewExport(rrSets); string(export) != want {
+		t.Errorf("Unexpected export:\n%s", export)
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	rrSets, err := ParseZoneFile(strings.NewReader(testZoneFile), "")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	roundTripped, err := NewExport(rrSets).RRsets()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if !reflect.DeepEqual(rrSets, roundTripped) {
+		t.Errorf("Round trip changed RRsets:\n%s\n%s", NewExport(rrSets), NewExport(roundTripped))
+	}
+}
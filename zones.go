@@ -2,8 +2,12 @@ package powerdns
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"iter"
 	"net/http"
+	"net/url"
 	"path"
 )
 
@@ -47,6 +51,11 @@ type AxfrRetrieveResult struct {
 	Result *string `json:"result,omitempty"`
 }
 
+// RectifyResult structure with JSON API metadata
+type RectifyResult struct {
+	Result *string `json:"result,omitempty"`
+}
+
 // Export string type
 type Export string
 
@@ -82,21 +91,191 @@ const (
 	ConsumerZoneKind ZoneKind = "Consumer"
 )
 
-// List retrieves a list of Zones
-func (z *ZonesService) List(ctx context.Context) ([]Zone, error) {
-	req, err := z.client.newRequest(ctx, http.MethodGet, path.Join("servers", z.client.VHost, "zones"), nil, nil)
-	if err != nil {
-		return nil, err
+// ListOption is a functional option for ZonesService.List and ZonesService.All.
+type ListOption func(*listOptions)
+
+type listOptions struct {
+	zone          string
+	withoutDNSSEC bool
+	account       *string
+	kind          *ZoneKind
+}
+
+// WithZoneName lists only the zone with the given name.
+func WithZoneName(name string) ListOption {
+	return func(o *listOptions) {
+		o.zone = makeDomainCanonical(name)
+	}
+}
+
+// WithoutDNSSEC skips determining the DNSSEC status of each zone, which is expensive on servers with many zones.
+// The DNSsec field of the listed zones is not set then.
+func WithoutDNSSEC() ListOption {
+	return func(o *listOptions) {
+		o.withoutDNSSEC = true
+	}
+}
+
+// WithAccount lists only the zones of the given account. The server returns all zones, which are filtered by the client.
+func WithAccount(account string) ListOption {
+	return func(o *listOptions) {
+		o.account = &account
+	}
+}
+
+// WithKind lists only the zones of the given kind. The server returns all zones, which are filtered by the client.
+func WithKind(kind ZoneKind) ListOption {
+	return func(o *listOptions) {
+		o.kind = &kind
+	}
+}
+
+func (o *listOptions) query() *url.Values {
+	query := &url.Values{}
+	if o.zone != "" {
+		query.Set("zone", o.zone)
+	}
+	if o.withoutDNSSEC {
+		query.Set("dnssec", "false")
+	}
+	return query
+}
+
+func (o *listOptions) matches(zone *Zone) bool {
+	if o.account != nil && StringValue(zone.Account) != *o.account {
+		return false
 	}
+	return o.kind == nil || (zone.Kind != nil && *zone.Kind == *o.kind)
+}
+
+// List retrieves a list of Zones
+func (z *ZonesService) List(ctx context.Context, options ...ListOption) ([]Zone, error) {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "List"})
 
 	zones := make([]Zone, 0)
-	_, err = z.client.do(req, &zones)
-	return zones, err
+	for zone, err := range z.All(ctx, options...) {
+		if err != nil {
+			return zones, err
+		}
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
+
+// All iterates over the Zones, which are decoded one by one while the response is read, so that the memory usage does not grow with the number of zones.
+// The iteration ends after the first error.
+func (z *ZonesService) All(ctx context.Context, options ...ListOption) iter.Seq2[Zone, error] {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "All"})
+
+	o := &listOptions{}
+	for _, option := range options {
+		option(o)
+	}
+
+	return func(yield func(Zone, error) bool) {
+		req, err := z.client.newRequest(ctx, http.MethodGet, path.Join("servers", z.client.VHost, "zones"), o.query(), nil)
+		if err != nil {
+			yield(Zone{}, err)
+			return
+		}
+
+		resp, err := z.client.do(req, nil)
+		if err != nil {
+			yield(Zone{}, err)
+			return
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		decoder := json.NewDecoder(resp.Body)
+		token, err := decoder.Token()
+		if err != nil {
+			yield(Zone{}, err)
+			return
+		}
+		if token != json.Delim('[') {
+			yield(Zone{}, fmt.Errorf("unexpected JSON token %v in zone list", token))
+			return
+		}
+		for decoder.More() {
+			var zone Zone
+			if err := decoder.Decode(&zone); err != nil {
+				yield(Zone{}, err)
+				return
+			}
+			if o.matches(&zone) && !yield(zone, nil) {
+				return
+			}
+		}
+	}
+}
+
+// GetOption is a functional option for ZonesService.Get.
+type GetOption func(*getOptions)
+
+type getOptions struct {
+	withoutRRsets   bool
+	rrSetName       string
+	rrSetType       *RRType
+	withoutDisabled bool
+}
+
+// WithoutRRsets retrieves only the zone attributes, e.g. its serial, which avoids transferring the RRsets of large zones.
+func WithoutRRsets() GetOption {
+	return func(o *getOptions) {
+		o.withoutRRsets = true
+	}
+}
+
+// WithRRsetName retrieves only the RRsets with the given name.
+func WithRRsetName(name string) GetOption {
+	return func(o *getOptions) {
+		o.rrSetName = makeDomainCanonical(name)
+	}
+}
+
+// WithRRsetType retrieves only the RRsets of the given type. PowerDNS applies it only in combination with WithRRsetName.
+func WithRRsetType(recordType RRType) GetOption {
+	return func(o *getOptions) {
+		o.rrSetType = &recordType
+	}
+}
+
+// WithoutDisabledRecords omits disabled records from the retrieved RRsets.
+func WithoutDisabledRecords() GetOption {
+	return func(o *getOptions) {
+		o.withoutDisabled = true
+	}
+}
+
+func (o *getOptions) query() *url.Values {
+	query := &url.Values{}
+	if o.withoutRRsets {
+		query.Set("rrsets", "false")
+	}
+	if o.rrSetName != "" {
+		query.Set("rrset_name", o.rrSetName)
+	}
+	if o.rrSetType != nil {
+		query.Set("rrset_type", string(*o.rrSetType))
+	}
+	if o.withoutDisabled {
+		query.Set("include_disabled", "false")
+	}
+	return query
 }
 
 // Get returns a certain Zone for a given domain
-func (z *ZonesService) Get(ctx context.Context, domain string) (*Zone, error) {
-	req, err := z.client.newRequest(ctx, http.MethodGet, path.Join("servers", z.client.VHost, "zones", makeDomainCanonical(domain)), nil, nil)
+func (z *ZonesService) Get(ctx context.Context, domain string, options ...GetOption) (*Zone, error) {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Get", Zone: makeDomainCanonical(domain)})
+
+	o := &getOptions{}
+	for _, option := range options {
+		option(o)
+	}
+
+	req, err := z.client.newRequest(ctx, http.MethodGet, path.Join("servers", z.client.VHost, "zones", zoneID(domain)), o.query(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -108,6 +287,8 @@ func (z *ZonesService) Get(ctx context.Context, domain string) (*Zone, error) {
 
 // AddNative creates a new native zone
 func (z *ZonesService) AddNative(ctx context.Context, domain string, dnssec bool, nsec3Param string, nsec3Narrow bool, soaEdit, soaEditApi string, apiRectify bool, nameservers []string) (*Zone, error) {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "AddNative", Zone: makeDomainCanonical(domain)})
+
 	zone := Zone{
 		Name:        String(domain),
 		Kind:        ZoneKindPtr(NativeZoneKind),
@@ -126,6 +307,8 @@ func (z *ZonesService) AddNative(ctx context.Context, domain string, dnssec bool
 
 // AddMaster creates a new master zone
 func (z *ZonesService) AddMaster(ctx context.Context, domain string, dnssec bool, nsec3Param string, nsec3Narrow bool, soaEdit, soaEditApi string, apiRectify bool, nameservers []string) (*Zone, error) {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "AddMaster", Zone: makeDomainCanonical(domain)})
+
 	zone := Zone{
 		Name:        String(domain),
 		Kind:        ZoneKindPtr(MasterZoneKind),
@@ -144,6 +327,8 @@ func (z *ZonesService) AddMaster(ctx context.Context, domain string, dnssec bool
 
 // AddSlave creates a new slave zone
 func (z *ZonesService) AddSlave(ctx context.Context, domain string, masters []string) (*Zone, error) {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "AddSlave", Zone: makeDomainCanonical(domain)})
+
 	zone := Zone{
 		Name:    String(domain),
 		Kind:    ZoneKindPtr(SlaveZoneKind),
@@ -154,6 +339,8 @@ func (z *ZonesService) AddSlave(ctx context.Context, domain string, masters []st
 
 // Add pre-created zone
 func (z *ZonesService) Add(ctx context.Context, zone *Zone) (*Zone, error) {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Add", Zone: makeDomainCanonical(StringValue(zone.Name))})
+
 	return z.postZone(ctx, zone)
 }
 
@@ -173,6 +360,8 @@ func (z *ZonesService) postZone(ctx context.Context, zone *Zone) (*Zone, error)
 
 // Change modifies an existing zone
 func (z *ZonesService) Change(ctx context.Context, domain string, zone *Zone) error {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Change", Zone: makeDomainCanonical(domain)})
+
 	zone.ID = nil
 	zone.Name = nil
 	zone.Type = nil
@@ -181,7 +370,7 @@ func (z *ZonesService) Change(ctx context.Context, domain string, zone *Zone) er
 		zone.Nsec3Param = nil
 	}
 
-	req, err := z.client.newRequest(ctx, http.MethodPut, path.Join("servers", z.client.VHost, "zones", makeDomainCanonical(domain)), nil, zone)
+	req, err := z.client.newRequest(ctx, http.MethodPut, path.Join("servers", z.client.VHost, "zones", zoneID(domain)), nil, zone)
 	if err != nil {
 		return err
 	}
@@ -192,7 +381,9 @@ func (z *ZonesService) Change(ctx context.Context, domain string, zone *Zone) er
 
 // Delete removes a certain Zone for a given domain
 func (z *ZonesService) Delete(ctx context.Context, domain string) error {
-	req, err := z.client.newRequest(ctx, http.MethodDelete, path.Join("servers", z.client.VHost, "zones", makeDomainCanonical(domain)), nil, nil)
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Delete", Zone: makeDomainCanonical(domain)})
+
+	req, err := z.client.newRequest(ctx, http.MethodDelete, path.Join("servers", z.client.VHost, "zones", zoneID(domain)), nil, nil)
 	if err != nil {
 		return err
 	}
@@ -203,7 +394,9 @@ func (z *ZonesService) Delete(ctx context.Context, domain string) error {
 
 // Notify sends a DNS notify packet to all slaves
 func (z *ZonesService) Notify(ctx context.Context, domain string) (*NotifyResult, error) {
-	req, err := z.client.newRequest(ctx, http.MethodPut, path.Join("servers", z.client.VHost, "zones", makeDomainCanonical(domain), "notify"), nil, nil)
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Notify", Zone: makeDomainCanonical(domain)})
+
+	req, err := z.client.newRequest(ctx, http.MethodPut, path.Join("servers", z.client.VHost, "zones", zoneID(domain), "notify"), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -215,7 +408,9 @@ func (z *ZonesService) Notify(ctx context.Context, domain string) (*NotifyResult
 
 // AxfrRetrieve requests a axfr transfer from the master to requesting slave
 func (z *ZonesService) AxfrRetrieve(ctx context.Context, domain string) (*AxfrRetrieveResult, error) {
-	req, err := z.client.newRequest(ctx, http.MethodPut, path.Join("servers", z.client.VHost, "zones", makeDomainCanonical(domain), "axfr-retrieve"), nil, nil)
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "AxfrRetrieve", Zone: makeDomainCanonical(domain)})
+
+	req, err := z.client.newRequest(ctx, http.MethodPut, path.Join("servers", z.client.VHost, "zones", zoneID(domain), "axfr-retrieve"), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -225,9 +420,25 @@ func (z *ZonesService) AxfrRetrieve(ctx context.Context, domain string) (*AxfrRe
 	return axfrRetrieveResult, err
 }
 
+// Rectify calculates the ordername and auth fields of all records of a DNSSEC zone
+func (z *ZonesService) Rectify(ctx context.Context, domain string) (*RectifyResult, error) {
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Rectify", Zone: makeDomainCanonical(domain)})
+
+	req, err := z.client.newRequest(ctx, http.MethodPut, path.Join("servers", z.client.VHost, "zones", zoneID(domain), "rectify"), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rectifyResult := &RectifyResult{}
+	_, err = z.client.do(req, rectifyResult)
+	return rectifyResult, err
+}
+
 // Export returns a BIND-like Zone file
 func (z *ZonesService) Export(ctx context.Context, domain string) (Export, error) {
-	req, err := z.client.newRequest(ctx, http.MethodGet, path.Join("servers", z.client.VHost, "zones", makeDomainCanonical(domain), "export"), nil, nil)
+	ctx = withOperation(ctx, Operation{Service: "Zones", Method: "Export", Zone: makeDomainCanonical(domain)})
+
+	req, err := z.client.newRequest(ctx, http.MethodGet, path.Join("servers", z.client.VHost, "zones", zoneID(domain), "export"), nil, nil)
 	if err != nil {
 		return "", err
 	}
@@ -3,10 +3,12 @@ package powerdns
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"testing"
@@ -292,6 +294,21 @@ func registerZoneMockResponder(testDomain string, zoneKind ZoneKind) {
 		},
 	)
 
+	httpmock.RegisterResponder("PUT", generateTestAPIVHostURL()+"/zones/"+makeDomainCanonical(testDomain)+"/rectify",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			if req.Body != nil {
+				log.Print("Request body is not nil")
+				return httpmock.NewBytesResponse(http.StatusBadRequest, []byte{}), nil
+			}
+
+			return httpmock.NewStringResponse(http.StatusOK, "{\"result\":\"Rectified\"}"), nil
+		},
+	)
+
 	httpmock.RegisterResponder("PUT", generateTestAPIVHostURL()+"/zones/"+makeDomainCanonical(testDomain)+"/axfr-retrieve",
 		func(req *http.Request) (*http.Response, error) {
 			if res := verifyAPIKey(req); res != nil {
@@ -332,6 +349,131 @@ func TestListZonesError(t *testing.T) {
 	}
 }
 
+func registerZonesStreamMockResponder(body string, queries *[]url.Values) {
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/zones",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			*queries = append(*queries, req.URL.Query())
+			return httpmock.NewStringResponse(http.StatusOK, body), nil
+		},
+	)
+}
+
+func TestListZonesWithOptions(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("zone listings are served by mocks")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var queries []url.Values
+	registerZonesStreamMockResponder(`[
+		{"name": "a.example.", "kind": "Native", "account": "ops"},
+		{"name": "b.example.", "kind": "Slave", "account": "ops"},
+		{"name": "c.example.", "kind": "Native"},
+		{"name": "d.example."}
+	]`, &queries)
+
+	testCases := []struct {
+		options   []ListOption
+		wantZones string
+		wantQuery string
+	}{
+		{nil, "a.example. b.example. c.example. d.example.", ""},
+		{[]ListOption{WithAccount("ops")}, "a.example. b.example.", ""},
+		{[]ListOption{WithKind(NativeZoneKind)}, "a.example. c.example.", ""},
+		{[]ListOption{WithAccount(""), WithKind(NativeZoneKind)}, "c.example.", ""},
+		{[]ListOption{WithZoneName("a.example"), WithoutDNSSEC()}, "a.example. b.example. c.example. d.example.", "dnssec=false&zone=a.example."},
+	}
+
+	p := initialisePowerDNSTestClient()
+	for i, tc := range testCases {
+		zones, err := p.Zones.List(context.Background(), tc.options...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		names := make([]string, len(zones))
+		for j, zone := range zones {
+			names[j] = *zone.Name
+		}
+		if strings.Join(names, " ") != tc.wantZones {
+			t.Errorf("TestCase%d: unexpected zones %v", i, names)
+		}
+		if queries[i].Encode() != tc.wantQuery {
+			t.Errorf("TestCase%d: unexpected query %q", i, queries[i].Encode())
+		}
+	}
+}
+
+func TestAllZones(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("zone listings are served by mocks")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var queries []url.Values
+	registerZonesStreamMockResponder(`[{"name": "a.example."}, {"name": "b.example."}, {"name": "c.example."}]`, &queries)
+
+	p := initialisePowerDNSTestClient()
+	var names []string
+	for zone, err := range p.Zones.All(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		names = append(names, *zone.Name)
+		if len(names) == 2 {
+			break
+		}
+	}
+	if strings.Join(names, " ") != "a.example. b.example." {
+		t.Errorf("unexpected zones %v", names)
+	}
+}
+
+func TestAllZonesError(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("zone listings are served by mocks")
+	}
+	testCases := []struct {
+		body      string
+		wantZones int
+	}{
+		{``, 0},
+		{`{"error": "not a list"}`, 0},
+		{`[{"name": "a.example."}, {"name": 1}]`, 1},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TestCase%d", i), func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			var queries []url.Values
+			registerZonesStreamMockResponder(tc.body, &queries)
+
+			p := initialisePowerDNSTestClient()
+			zones, err := p.Zones.List(context.Background())
+			if err == nil || len(zones) != tc.wantZones {
+				t.Errorf("unexpected zones %+v: %v", zones, err)
+			}
+		})
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/zones", httpmock.NewStringResponder(http.StatusInternalServerError, "Internal Server Error"))
+
+	p := initialisePowerDNSTestClient()
+	for _, err := range p.Zones.All(context.Background()) {
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Operation.Method != "All" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
+
 func TestGetZone(t *testing.T) {
 	testDomain := generateNativeZone(true)
 
@@ -349,6 +491,45 @@ func TestGetZone(t *testing.T) {
 	}
 }
 
+func TestGetZoneWithOptions(t *testing.T) {
+	if httpmock.Disabled() {
+		t.Skip("zone listings are served by mocks")
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var queries []url.Values
+	httpmock.RegisterResponder(http.MethodGet, generateTestAPIVHostURL()+"/zones/example.com.",
+		func(req *http.Request) (*http.Response, error) {
+			if res := verifyAPIKey(req); res != nil {
+				return res, nil
+			}
+
+			queries = append(queries, req.URL.Query())
+			return httpmock.NewJsonResponse(http.StatusOK, Zone{Name: String("example.com."), Serial: Uint32(1337)})
+		},
+	)
+
+	testCases := []struct {
+		options   []GetOption
+		wantQuery string
+	}{
+		{nil, ""},
+		{[]GetOption{WithoutRRsets()}, "rrsets=false"},
+		{[]GetOption{WithRRsetName("www.example.com"), WithRRsetType(RRTypeA), WithoutDisabledRecords()}, "include_disabled=false&rrset_name=www.example.com.&rrset_type=A"},
+	}
+
+	p := initialisePowerDNSTestClient()
+	for i, tc := range testCases {
+		zone, err := p.Zones.Get(context.Background(), "example.com", tc.options...)
+		if err != nil || *zone.Serial != 1337 {
+			t.Fatalf("TestCase%d: unexpected zone %+v: %v", i, zone, err)
+		}
+		if queries[i].Encode() != tc.wantQuery {
+			t.Errorf("TestCase%d: unexpected query %q", i, queries[i].Encode())
+		}
+	}
+}
+
 func TestGetZonesError(t *testing.T) {
 	testDomain := generateNativeZone(false)
 	p := initialisePowerDNSTestClient()
@@ -616,6 +797,32 @@ func TestNotifyError(t *testing.T) {
 	}
 }
 
+func TestRectify(t *testing.T) {
+	testDomain := generateNativeZone(true)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerZoneMockResponder(testDomain, NativeZoneKind)
+
+	p := initialisePowerDNSTestClient()
+	rectifyResult, err := p.Zones.Rectify(context.Background(), testDomain)
+	if err != nil {
+		t.Errorf("%s", err)
+	}
+	if *rectifyResult.Result != "Rectified" {
+		t.Error("Zone was not rectified successfully")
+	}
+}
+
+func TestRectifyError(t *testing.T) {
+	testDomain := generateNativeZone(false)
+	p := initialisePowerDNSTestClient()
+	p.BaseURL = "://"
+	if _, err := p.Zones.Rectify(context.Background(), testDomain); err == nil {
+		t.Error("error is nil")
+	}
+}
+
 func TestExport(t *testing.T) {
 	testDomain := generateNativeZone(true)
 